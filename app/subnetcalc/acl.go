@@ -0,0 +1,207 @@
+package subnetcalc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ACLFormat selects the firewall syntax produced by RenderACL.
+type ACLFormat string
+
+// Supported ACL formats.
+const (
+	ACLIptables ACLFormat = "iptables"
+	ACLNftables ACLFormat = "nftables"
+	ACLCisco    ACLFormat = "cisco"
+	ACLJunos    ACLFormat = "junos"
+	ACLAWSSG    ACLFormat = "aws-sg"
+)
+
+// ACLFormats lists every format RenderACL understands.
+var ACLFormats = []ACLFormat{ACLIptables, ACLNftables, ACLCisco, ACLJunos, ACLAWSSG}
+
+// ACLOptions controls how RenderACL renders rules.
+//
+// Name is the ACL, filter or chain the rules are added to; when empty a
+// format-specific default is used (INPUT for iptables, input for nftables,
+// SNC for Cisco and Junos).
+type ACLOptions struct {
+	Format ACLFormat
+	Deny   bool
+	Name   string
+}
+
+// RenderACL renders one rule per prefix that permits (or denies) traffic
+// sourced from it. Host bits are masked off before rendering, and Cisco rules
+// use the wildcard mask of the prefix.
+//
+// AWS security groups cannot express deny rules, so Deny is rejected for
+// ACLAWSSG.
+func RenderACL(prefixes []netip.Prefix, opts ACLOptions) (string, error) {
+	masked := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if !prefix.IsValid() {
+			return "", errors.New("invalid prefix")
+		}
+		masked = append(masked, prefix.Masked())
+	}
+
+	switch opts.Format {
+	case ACLIptables:
+		return renderIptables(masked, opts), nil
+	case ACLNftables:
+		return renderNftables(masked, opts), nil
+	case ACLCisco:
+		return renderCisco(masked, opts), nil
+	case ACLJunos:
+		return renderJunos(masked, opts), nil
+	case ACLAWSSG:
+		if opts.Deny {
+			return "", errors.New("AWS security groups do not support deny rules")
+		}
+		return renderAWSSecurityGroup(masked)
+	default:
+		return "", fmt.Errorf("unknown ACL format %q", opts.Format)
+	}
+}
+
+func aclName(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func renderIptables(prefixes []netip.Prefix, opts ACLOptions) string {
+	chain := aclName(opts.Name, "INPUT")
+	target := "ACCEPT"
+	if opts.Deny {
+		target = "DROP"
+	}
+	var b strings.Builder
+	for _, prefix := range prefixes {
+		tool := "iptables"
+		if prefix.Addr().Is6() {
+			tool = "ip6tables"
+		}
+		fmt.Fprintf(&b, "%s -A %s -s %s -j %s\n", tool, chain, prefix, target)
+	}
+	return b.String()
+}
+
+func renderNftables(prefixes []netip.Prefix, opts ACLOptions) string {
+	chain := aclName(opts.Name, "input")
+	verdict := "accept"
+	if opts.Deny {
+		verdict = "drop"
+	}
+	var b strings.Builder
+	for _, prefix := range prefixes {
+		family := "ip"
+		if prefix.Addr().Is6() {
+			family = "ip6"
+		}
+		fmt.Fprintf(&b, "add rule inet filter %s %s saddr %s %s\n", chain, family, prefix, verdict)
+	}
+	return b.String()
+}
+
+func renderCisco(prefixes []netip.Prefix, opts ACLOptions) string {
+	name := aclName(opts.Name, "SNC")
+	action := "permit"
+	if opts.Deny {
+		action = "deny"
+	}
+
+	var v4, v6 []string
+	for _, prefix := range prefixes {
+		if prefix.Addr().Is6() {
+			v6 = append(v6, fmt.Sprintf(" %s ipv6 %s any", action, ciscoIPv6Source(prefix)))
+			continue
+		}
+		v4 = append(v4, fmt.Sprintf(" %s ip %s any", action, ciscoIPv4Source(prefix)))
+	}
+
+	var b strings.Builder
+	if len(v4) > 0 {
+		fmt.Fprintf(&b, "ip access-list extended %s\n%s\n", name, strings.Join(v4, "\n"))
+	}
+	if len(v6) > 0 {
+		fmt.Fprintf(&b, "ipv6 access-list %s\n%s\n", name, strings.Join(v6, "\n"))
+	}
+	return b.String()
+}
+
+func ciscoIPv4Source(prefix netip.Prefix) string {
+	switch prefix.Bits() {
+	case 0:
+		return "any"
+	case 32:
+		return "host " + prefix.Addr().String()
+	}
+	return prefix.Addr().String() + " " + uint32ToAddr(calcMasks(prefix).WildcardMask).String()
+}
+
+func ciscoIPv6Source(prefix netip.Prefix) string {
+	switch prefix.Bits() {
+	case 0:
+		return "any"
+	case 128:
+		return "host " + prefix.Addr().String()
+	}
+	return prefix.String()
+}
+
+func renderJunos(prefixes []netip.Prefix, opts ACLOptions) string {
+	name := aclName(opts.Name, "SNC")
+	action := "accept"
+	if opts.Deny {
+		action = "discard"
+	}
+	var b strings.Builder
+	for i, prefix := range prefixes {
+		family := "inet"
+		if prefix.Addr().Is6() {
+			family = "inet6"
+		}
+		term := fmt.Sprintf("set firewall family %s filter %s term snc-%d", family, name, i+1)
+		fmt.Fprintf(&b, "%s from source-address %s\n", term, prefix)
+		fmt.Fprintf(&b, "%s then %s\n", term, action)
+	}
+	return b.String()
+}
+
+type awsIPPermission struct {
+	IPProtocol string         `json:"IpProtocol"`
+	IPRanges   []awsIPRange   `json:"IpRanges,omitempty"`
+	IPv6Ranges []awsIPv6Range `json:"Ipv6Ranges,omitempty"`
+}
+
+type awsIPRange struct {
+	CidrIP string `json:"CidrIp"`
+}
+
+type awsIPv6Range struct {
+	CidrIPv6 string `json:"CidrIpv6"`
+}
+
+// renderAWSSecurityGroup renders the --ip-permissions JSON accepted by
+// aws ec2 authorize-security-group-ingress.
+func renderAWSSecurityGroup(prefixes []netip.Prefix) (string, error) {
+	perm := awsIPPermission{IPProtocol: "-1"}
+	for _, prefix := range prefixes {
+		if prefix.Addr().Is6() {
+			perm.IPv6Ranges = append(perm.IPv6Ranges, awsIPv6Range{CidrIPv6: prefix.String()})
+		} else {
+			perm.IPRanges = append(perm.IPRanges, awsIPRange{CidrIP: prefix.String()})
+		}
+	}
+	out, err := json.MarshalIndent([]awsIPPermission{perm}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
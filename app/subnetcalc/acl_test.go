@@ -0,0 +1,139 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleRenderACL() {
+	prefixes := []netip.Prefix{netip.MustParsePrefix("172.16.38.94/27")}
+	acl, _ := RenderACL(prefixes, ACLOptions{Format: ACLCisco, Name: "MGMT"})
+	fmt.Print(acl)
+	// Output:
+	// ip access-list extended MGMT
+	//  permit ip 172.16.38.64 0.0.0.31 any
+}
+
+func TestRenderACL(t *testing.T) {
+	prefixes := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.7/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+
+	tests := []struct {
+		name string
+		opts ACLOptions
+		want string
+	}{
+		{
+			"iptables permit",
+			ACLOptions{Format: ACLIptables},
+			"iptables -A INPUT -s 10.0.0.0/8 -j ACCEPT\n" +
+				"iptables -A INPUT -s 192.168.1.7/32 -j ACCEPT\n" +
+				"ip6tables -A INPUT -s 2001:db8::/32 -j ACCEPT\n",
+		},
+		{
+			"iptables deny custom chain",
+			ACLOptions{Format: ACLIptables, Deny: true, Name: "FORWARD"},
+			"iptables -A FORWARD -s 10.0.0.0/8 -j DROP\n" +
+				"iptables -A FORWARD -s 192.168.1.7/32 -j DROP\n" +
+				"ip6tables -A FORWARD -s 2001:db8::/32 -j DROP\n",
+		},
+		{
+			"nftables",
+			ACLOptions{Format: ACLNftables, Deny: true},
+			"add rule inet filter input ip saddr 10.0.0.0/8 drop\n" +
+				"add rule inet filter input ip saddr 192.168.1.7/32 drop\n" +
+				"add rule inet filter input ip6 saddr 2001:db8::/32 drop\n",
+		},
+		{
+			"cisco",
+			ACLOptions{Format: ACLCisco},
+			"ip access-list extended SNC\n" +
+				" permit ip 10.0.0.0 0.255.255.255 any\n" +
+				" permit ip host 192.168.1.7 any\n" +
+				"ipv6 access-list SNC\n" +
+				" permit ipv6 2001:db8::/32 any\n",
+		},
+		{
+			"junos",
+			ACLOptions{Format: ACLJunos, Deny: true, Name: "EDGE"},
+			"set firewall family inet filter EDGE term snc-1 from source-address 10.0.0.0/8\n" +
+				"set firewall family inet filter EDGE term snc-1 then discard\n" +
+				"set firewall family inet filter EDGE term snc-2 from source-address 192.168.1.7/32\n" +
+				"set firewall family inet filter EDGE term snc-2 then discard\n" +
+				"set firewall family inet6 filter EDGE term snc-3 from source-address 2001:db8::/32\n" +
+				"set firewall family inet6 filter EDGE term snc-3 then discard\n",
+		},
+		{
+			"aws security group",
+			ACLOptions{Format: ACLAWSSG},
+			`[
+  {
+    "IpProtocol": "-1",
+    "IpRanges": [
+      {
+        "CidrIp": "10.0.0.0/8"
+      },
+      {
+        "CidrIp": "192.168.1.7/32"
+      }
+    ],
+    "Ipv6Ranges": [
+      {
+        "CidrIpv6": "2001:db8::/32"
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderACL(prefixes, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderACL_CiscoWildcardMasks(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"0.0.0.0/0", " permit ip any any\n"},
+		{"10.1.2.3/23", " permit ip 10.1.2.0 0.0.1.255 any\n"},
+		{"172.16.0.0/12", " permit ip 172.16.0.0 0.15.255.255 any\n"},
+		{"192.168.10.0/30", " permit ip 192.168.10.0 0.0.0.3 any\n"},
+		{"192.168.10.4/31", " permit ip 192.168.10.4 0.0.0.1 any\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := RenderACL([]netip.Prefix{netip.MustParsePrefix(tt.input)}, ACLOptions{Format: ACLCisco})
+			require.NoError(t, err)
+			assert.Equal(t, "ip access-list extended SNC\n"+tt.want, got)
+		})
+	}
+}
+
+func TestRenderACL_Errors(t *testing.T) {
+	prefixes := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	_, err := RenderACL(prefixes, ACLOptions{Format: ACLAWSSG, Deny: true})
+	assert.EqualError(t, err, "AWS security groups do not support deny rules")
+
+	_, err = RenderACL(prefixes, ACLOptions{Format: "pf"})
+	assert.EqualError(t, err, `unknown ACL format "pf"`)
+
+	_, err = RenderACL([]netip.Prefix{{}}, ACLOptions{Format: ACLCisco})
+	assert.EqualError(t, err, "invalid prefix")
+}
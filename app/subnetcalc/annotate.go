@@ -0,0 +1,239 @@
+package subnetcalc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Annotation is what offline data files say about an address: the network
+// it was found in, its origin AS and the organization and country it is
+// registered to. Fields a data file does not provide are left empty.
+type Annotation struct {
+	Network      netip.Prefix `json:"network" yaml:"network"`
+	ASN          uint32       `json:"asn,omitempty" yaml:"asn,omitempty"`
+	Organization string       `json:"organization,omitempty" yaml:"organization,omitempty"`
+	Country      string       `json:"country,omitempty" yaml:"country,omitempty"`
+	Registry     string       `json:"registry,omitempty" yaml:"registry,omitempty"`
+}
+
+// Annotator looks up annotations for a prefix. Prefixes are looked up by
+// their network address, so a prefix wider than the data file's networks is
+// annotated with the first one.
+type Annotator interface {
+	Annotate(prefix netip.Prefix) (Annotation, bool)
+}
+
+// AnnotationTrie is a binary trie of annotated prefixes for longest-prefix
+// matching. The zero value is an empty trie ready to use.
+type AnnotationTrie struct {
+	root4, root6 *trieNode
+}
+
+type trieNode struct {
+	children   [2]*trieNode
+	annotation *Annotation
+}
+
+// Insert annotates prefix, replacing any earlier annotation of the same
+// prefix. Host bits are ignored.
+func (t *AnnotationTrie) Insert(prefix netip.Prefix, a Annotation) {
+	if !prefix.IsValid() {
+		return
+	}
+	prefix = prefix.Masked()
+	a.Network = prefix
+
+	root := &t.root4
+	if prefix.Addr().Is6() {
+		root = &t.root6
+	}
+	if *root == nil {
+		*root = &trieNode{}
+	}
+	n := *root
+	b := prefix.Addr().AsSlice()
+	for i := 0; i < prefix.Bits(); i++ {
+		bit := b[i/8] >> (7 - i%8) & 1
+		if n.children[bit] == nil {
+			n.children[bit] = &trieNode{}
+		}
+		n = n.children[bit]
+	}
+	n.annotation = &a
+}
+
+// Annotate returns the annotation of the longest inserted prefix containing
+// the network address of prefix.
+func (t *AnnotationTrie) Annotate(prefix netip.Prefix) (Annotation, bool) {
+	if !prefix.IsValid() {
+		return Annotation{}, false
+	}
+	addr := prefix.Masked().Addr()
+	n := t.root4
+	if addr.Is6() {
+		n = t.root6
+	}
+
+	var best *Annotation
+	b := addr.AsSlice()
+	for i := 0; n != nil; i++ {
+		if n.annotation != nil {
+			best = n.annotation
+		}
+		if i == len(b)*8 {
+			break
+		}
+		n = n.children[b[i/8]>>(7-i%8)&1]
+	}
+	if best == nil {
+		return Annotation{}, false
+	}
+	return *best, true
+}
+
+// NewDelegatedAnnotator builds a trie from RIR delegated-stats records,
+// annotating allocated and assigned space with its country and registry.
+func NewDelegatedAnnotator(records []DelegatedRecord) *AnnotationTrie {
+	t := &AnnotationTrie{}
+	for _, r := range records {
+		if r.Status != "allocated" && r.Status != "assigned" {
+			continue
+		}
+		for _, p := range r.Prefixes {
+			t.Insert(p, Annotation{Country: r.Country, Registry: r.Registry})
+		}
+	}
+	return t
+}
+
+// MMDBAnnotator annotates prefixes from a MaxMind DB file such as the
+// GeoLite2 ASN or Country databases. The file is searched in place; no
+// network access is made.
+type MMDBAnnotator struct {
+	reader *maxminddb.Reader
+}
+
+// mmdbRecord holds the fields read from GeoLite2/GeoIP2 style databases.
+type mmdbRecord struct {
+	ASN          uint32 `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+	Country      struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// OpenMMDB opens a MaxMind DB file.
+func OpenMMDB(path string) (*MMDBAnnotator, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &MMDBAnnotator{reader: reader}, nil
+}
+
+// Annotate looks up the network address of prefix.
+func (m *MMDBAnnotator) Annotate(prefix netip.Prefix) (Annotation, bool) {
+	if !prefix.IsValid() {
+		return Annotation{}, false
+	}
+	var record mmdbRecord
+	network, ok, err := m.reader.LookupNetwork(net.IP(prefix.Masked().Addr().AsSlice()), &record)
+	if err != nil || !ok {
+		return Annotation{}, false
+	}
+
+	a := Annotation{ASN: record.ASN, Organization: record.Organization, Country: record.Country.ISOCode}
+	if a.Country == "" {
+		a.Country = record.RegisteredCountry.ISOCode
+	}
+	if addr, ok := netip.AddrFromSlice(network.IP); ok {
+		ones, bits := network.Mask.Size()
+		if bits == 32 || (addr.Is4In6() && ones >= 96) {
+			if bits == 128 {
+				ones -= 96
+			}
+			addr = addr.Unmap()
+		}
+		a.Network = netip.PrefixFrom(addr, ones)
+	}
+	return a, true
+}
+
+// Close releases the database file.
+func (m *MMDBAnnotator) Close() error {
+	return m.reader.Close()
+}
+
+// Annotators combines several annotators. Each field of the result comes
+// from the first annotator that provides it, so an ASN database and a
+// delegated-stats file can be used together.
+type Annotators []Annotator
+
+// Annotate queries every annotator in order.
+func (as Annotators) Annotate(prefix netip.Prefix) (Annotation, bool) {
+	var result Annotation
+	found := false
+	for _, a := range as {
+		annotation, ok := a.Annotate(prefix)
+		if !ok {
+			continue
+		}
+		found = true
+		if !result.Network.IsValid() {
+			result.Network = annotation.Network
+		}
+		if result.ASN == 0 {
+			result.ASN = annotation.ASN
+		}
+		if result.Organization == "" {
+			result.Organization = annotation.Organization
+		}
+		if result.Country == "" {
+			result.Country = annotation.Country
+		}
+		if result.Registry == "" {
+			result.Registry = annotation.Registry
+		}
+	}
+	return result, found
+}
+
+// Close closes every annotator that holds an open file, such as an
+// MMDBAnnotator.
+func (as Annotators) Close() error {
+	var errs []error
+	for _, a := range as {
+		if c, ok := a.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadAnnotator opens an offline data file: a MaxMind DB when the name ends
+// in .mmdb, otherwise an RIR delegated-stats file.
+func LoadAnnotator(path string) (Annotator, error) {
+	if filepath.Ext(path) == ".mmdb" {
+		return OpenMMDB(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := ParseDelegatedStats(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewDelegatedAnnotator(records), nil
+}
@@ -0,0 +1,198 @@
+package subnetcalc
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationTrie(t *testing.T) {
+	var trie AnnotationTrie
+	trie.Insert(netip.MustParsePrefix("10.0.0.0/8"), Annotation{Organization: "corp"})
+	trie.Insert(netip.MustParsePrefix("10.1.2.3/16"), Annotation{Organization: "lab"})
+	trie.Insert(netip.MustParsePrefix("2001:db8::/32"), Annotation{Country: "NL"})
+	trie.Insert(netip.MustParsePrefix("::/0"), Annotation{Country: "ZZ"})
+
+	a, ok := trie.Annotate(netip.MustParsePrefix("10.1.200.0/24"))
+	require.True(t, ok)
+	assert.Equal(t, Annotation{Network: netip.MustParsePrefix("10.1.0.0/16"), Organization: "lab"}, a)
+
+	a, ok = trie.Annotate(netip.MustParsePrefix("10.2.0.0/16"))
+	require.True(t, ok)
+	assert.Equal(t, "corp", a.Organization)
+
+	a, ok = trie.Annotate(netip.MustParsePrefix("2001:db8::1/128"))
+	require.True(t, ok)
+	assert.Equal(t, "NL", a.Country)
+
+	a, ok = trie.Annotate(netip.MustParsePrefix("2001:db9::/32"))
+	require.True(t, ok)
+	assert.Equal(t, Annotation{Network: netip.MustParsePrefix("::/0"), Country: "ZZ"}, a)
+
+	_, ok = trie.Annotate(netip.MustParsePrefix("192.0.2.0/24"))
+	assert.False(t, ok)
+
+	var empty AnnotationTrie
+	_, ok = empty.Annotate(netip.MustParsePrefix("10.0.0.0/8"))
+	assert.False(t, ok)
+}
+
+func TestNewDelegatedAnnotator(t *testing.T) {
+	records, err := ParseDelegatedStats(strings.NewReader(delegatedSample))
+	require.NoError(t, err)
+	trie := NewDelegatedAnnotator(records)
+
+	a, ok := trie.Annotate(netip.MustParsePrefix("192.0.4.10/32"))
+	require.True(t, ok)
+	assert.Equal(t, Annotation{Network: netip.MustParsePrefix("192.0.4.0/24"), Country: "NL", Registry: "ripencc"}, a)
+
+	_, ok = trie.Annotate(netip.MustParsePrefix("198.51.100.0/24"))
+	assert.False(t, ok, "available space is not annotated")
+}
+
+func TestMMDBAnnotator(t *testing.T) {
+	path := writeTestMMDB(t)
+
+	annotator, err := LoadAnnotator(path)
+	require.NoError(t, err)
+	defer annotator.(*MMDBAnnotator).Close()
+
+	a, ok := annotator.Annotate(netip.MustParsePrefix("192.0.2.77/32"))
+	require.True(t, ok)
+	assert.Equal(t, Annotation{
+		Network:      netip.MustParsePrefix("192.0.2.0/24"),
+		ASN:          64500,
+		Organization: "Example Net",
+		Country:      "NL",
+	}, a)
+
+	_, ok = annotator.Annotate(netip.MustParsePrefix("192.0.3.0/24"))
+	assert.False(t, ok)
+
+	_, err = OpenMMDB(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
+
+func TestAnnotators(t *testing.T) {
+	var asn, geo AnnotationTrie
+	asn.Insert(netip.MustParsePrefix("192.0.2.0/24"), Annotation{ASN: 64500, Organization: "Example Net"})
+	geo.Insert(netip.MustParsePrefix("192.0.0.0/16"), Annotation{Country: "NL", Registry: "ripencc", Organization: "ignored"})
+
+	a, ok := Annotators{&asn, &geo}.Annotate(netip.MustParsePrefix("192.0.2.1/32"))
+	require.True(t, ok)
+	assert.Equal(t, Annotation{
+		Network:      netip.MustParsePrefix("192.0.2.0/24"),
+		ASN:          64500,
+		Organization: "Example Net",
+		Country:      "NL",
+		Registry:     "ripencc",
+	}, a)
+
+	_, ok = Annotators{&asn, &geo}.Annotate(netip.MustParsePrefix("10.0.0.0/8"))
+	assert.False(t, ok)
+}
+
+// closingAnnotator records whether it was closed.
+type closingAnnotator struct {
+	AnnotationTrie
+	closed bool
+}
+
+func (c *closingAnnotator) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAnnotators_Close(t *testing.T) {
+	closing := &closingAnnotator{}
+	require.NoError(t, Annotators{&AnnotationTrie{}, closing}.Close())
+	assert.True(t, closing.closed)
+	assert.NoError(t, Annotators(nil).Close())
+}
+
+func TestLoadAnnotator_Delegated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "delegated-ripencc-latest")
+	require.NoError(t, os.WriteFile(path, []byte(delegatedSample), 0o600))
+
+	annotator, err := LoadAnnotator(path)
+	require.NoError(t, err)
+	a, ok := annotator.Annotate(netip.MustParsePrefix("2.1.0.0/16"))
+	require.True(t, ok)
+	assert.Equal(t, "FR", a.Country)
+}
+
+// writeTestMMDB writes a minimal IPv4 MaxMind DB holding one record for
+// 192.0.2.0/24 and returns its path.
+func writeTestMMDB(t *testing.T) string {
+	t.Helper()
+	const nodeCount = 24
+
+	// One node per bit of 192.0.2.0/24; the last one points at the only
+	// data record, every other branch is empty.
+	network := netip.MustParseAddr("192.0.2.0").As4()
+	var db []byte
+	for i := 0; i < nodeCount; i++ {
+		records := [2]uint32{nodeCount, nodeCount}
+		next := uint32(i + 1)
+		if i == nodeCount-1 {
+			next = nodeCount + 16
+		}
+		records[network[i/8]>>(7-i%8)&1] = next
+		for _, r := range records {
+			db = append(db, byte(r>>16), byte(r>>8), byte(r))
+		}
+	}
+	db = append(db, make([]byte, 16)...)
+
+	db = append(db, mmdbMap(3)...)
+	db = append(db, mmdbString("autonomous_system_number")...)
+	db = append(db, mmdbUint(6, 64500)...)
+	db = append(db, mmdbString("autonomous_system_organization")...)
+	db = append(db, mmdbString("Example Net")...)
+	db = append(db, mmdbString("country")...)
+	db = append(db, mmdbMap(1)...)
+	db = append(db, mmdbString("iso_code")...)
+	db = append(db, mmdbString("NL")...)
+
+	db = append(db, "\xab\xcd\xefMaxMind.com"...)
+	db = append(db, mmdbMap(6)...)
+	db = append(db, mmdbString("node_count")...)
+	db = append(db, mmdbUint(6, nodeCount)...)
+	db = append(db, mmdbString("record_size")...)
+	db = append(db, mmdbUint(5, 24)...)
+	db = append(db, mmdbString("ip_version")...)
+	db = append(db, mmdbUint(5, 4)...)
+	db = append(db, mmdbString("database_type")...)
+	db = append(db, mmdbString("Test")...)
+	db = append(db, mmdbString("binary_format_major_version")...)
+	db = append(db, mmdbUint(5, 2)...)
+	db = append(db, mmdbString("binary_format_minor_version")...)
+	db = append(db, mmdbUint(5, 0)...)
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	require.NoError(t, os.WriteFile(path, db, 0o600))
+	return path
+}
+
+func mmdbMap(pairs int) []byte { return []byte{7<<5 | byte(pairs)} }
+
+func mmdbString(s string) []byte {
+	if len(s) >= 29 {
+		return append([]byte{2<<5 | 29, byte(len(s) - 29)}, s...)
+	}
+	return append([]byte{2<<5 | byte(len(s))}, s...)
+}
+
+// mmdbUint encodes v as a uint16 (type 5) or uint32 (type 6).
+func mmdbUint(typ byte, v uint32) []byte {
+	if typ == 5 {
+		return []byte{5<<5 | 2, byte(v >> 8), byte(v)}
+	}
+	return binary.BigEndian.AppendUint32([]byte{6<<5 | 4}, v)
+}
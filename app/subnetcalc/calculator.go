@@ -0,0 +1,240 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+)
+
+// IPv6Policy controls how a Calculator handles IPv6 prefixes.
+type IPv6Policy int
+
+// IPv6 policies.
+const (
+	// IPv6Reject fails with "IPv6 not supported yet", as CalcSubnetInfo
+	// always has.
+	IPv6Reject IPv6Policy = iota
+	// IPv6Allow calculates IPv6 prefixes. They have no broadcast address
+	// or subnet mask, and every address is usable.
+	IPv6Allow
+)
+
+// Calculator calculates subnet information. Build one with NewCalculator;
+// the zero value is not ready to use.
+type Calculator struct {
+	rfc3021   bool
+	provider  Provider
+	strict    bool
+	ipv6      IPv6Policy
+	annotator Annotator
+}
+
+// Option configures a Calculator.
+type Option func(*Calculator)
+
+// WithRFC3021 selects whether both addresses of an IPv4 /31 are usable, as on
+// point-to-point links (RFC 3021). It is enabled by default; when disabled a
+// /31 has no usable addresses.
+func WithRFC3021(enabled bool) Option {
+	return func(c *Calculator) { c.rfc3021 = enabled }
+}
+
+// WithProvider makes the Calculator apply the subnet size limits and
+// reserved addresses of a cloud provider.
+func WithProvider(provider Provider) Option {
+	return func(c *Calculator) { c.provider = provider }
+}
+
+// WithStrict makes the Calculator reject prefixes with host bits set with a
+// *HostBitsError instead of masking them.
+func WithStrict(strict bool) Option {
+	return func(c *Calculator) { c.strict = strict }
+}
+
+// WithIPv6Policy sets how IPv6 prefixes are handled. The default is
+// IPv6Reject.
+func WithIPv6Policy(policy IPv6Policy) Option {
+	return func(c *Calculator) { c.ipv6 = policy }
+}
+
+// WithAnnotator makes the Calculator annotate results with what annotator
+// knows about the prefix.
+func WithAnnotator(annotator Annotator) Option {
+	return func(c *Calculator) { c.annotator = annotator }
+}
+
+// NewCalculator returns a Calculator configured by opts.
+//
+// Example:
+//
+//	calc := NewCalculator(WithProvider(ProviderAWS), WithStrict(true))
+//	result, err := calc.Calculate(netip.MustParsePrefix("10.0.1.0/24"))
+func NewCalculator(opts ...Option) *Calculator {
+	c := &Calculator{rfc3021: true}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Result is the outcome of Calculator.Calculate.
+//
+// Input is the prefix as given and Prefix its canonical form. Broadcast
+// address, subnet mask and wildcard mask are only set for IPv4. FirstUsable
+// and LastUsable are unset when no address is usable, and Reserved is only
+// set when a provider is configured. IPv6 holds the extra notations of IPv6
+// prefixes, as returned by ParseIPv6 but without a zone. Multicast is set
+// for multicast groups and ranges, as described by AnalyzeMulticastPrefix.
+// Annotation is set when an annotator is configured and knows the prefix.
+// Results encode to JSON and YAML with snake_case keys, leaving out the
+// fields that are unset.
+type Result struct {
+	Input            netip.Prefix
+	Prefix           netip.Prefix
+	NetworkAddress   netip.Addr
+	BroadcastAddress netip.Addr
+	SubnetMask       netip.Addr
+	WildcardMask     netip.Addr
+	FirstUsable      netip.Addr
+	LastUsable       netip.Addr
+	TotalIPs         *big.Int
+	UsableIPs        *big.Int
+	Reserved         []ReservedAddress
+	IPv6             *IPv6Info
+	Multicast        *MulticastInfo
+	Annotation       *Annotation
+}
+
+// Normalized reports whether host bits were masked off the input.
+func (r Result) Normalized() bool {
+	return r.Input != r.Prefix
+}
+
+// SubnetInfo returns the IPv4 view of the result used by CalcSubnetInfo.
+// TotalIP is zero for IPv6 prefixes too large to count in a uint.
+func (r Result) SubnetInfo() SubnetInfo {
+	info := SubnetInfo{
+		NetworkAddress: r.NetworkAddress,
+		BroadcastIP:    r.BroadcastAddress,
+		SubnetMask:     r.SubnetMask,
+		Reserved:       r.Reserved,
+	}
+	if r.TotalIPs.IsUint64() {
+		info.TotalIP = uint(r.TotalIPs.Uint64())
+	}
+	return info
+}
+
+// Calculate calculates subnet information for prefix.
+func (c *Calculator) Calculate(prefix netip.Prefix) (Result, error) {
+	result, err := c.calculate(prefix)
+	if err != nil {
+		return Result{}, err
+	}
+	if result.Prefix.Addr().IsMulticast() {
+		info, err := AnalyzeMulticastPrefix(result.Prefix)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Multicast = &info
+	}
+	if c.annotator != nil {
+		if a, ok := c.annotator.Annotate(result.Prefix); ok {
+			result.Annotation = &a
+		}
+	}
+	return result, nil
+}
+
+func (c *Calculator) calculate(prefix netip.Prefix) (Result, error) {
+	if c.provider != "" {
+		if _, ok := providerRules[c.provider]; !ok {
+			return Result{}, fmt.Errorf("unknown provider %q", c.provider)
+		}
+	}
+	if !prefix.IsValid() {
+		return Result{}, errors.New("invalid prefix")
+	}
+	if prefix.Addr().Is6() && c.ipv6 != IPv6Allow {
+		return Result{}, errors.New("IPv6 not supported yet")
+	}
+	if c.strict {
+		if err := CheckHostBits(prefix); err != nil {
+			return Result{}, err
+		}
+	}
+
+	result := Result{Input: prefix, Prefix: prefix.Masked()}
+	if prefix.Addr().Is6() {
+		if c.provider != "" {
+			return Result{}, fmt.Errorf("%s reservations only apply to IPv4 subnets", c.provider)
+		}
+		result.NetworkAddress = result.Prefix.Addr()
+		result.FirstUsable = result.NetworkAddress
+		result.LastUsable = lastAddr(result.Prefix)
+		result.TotalIPs = new(big.Int).Lsh(big.NewInt(1), uint(128-prefix.Bits()))
+		result.UsableIPs = new(big.Int).Set(result.TotalIPs)
+		info := describeIPv6(prefix)
+		result.IPv6 = &info
+		return result, nil
+	}
+
+	info := calcIPv4SubnetInfo(prefix)
+	result.NetworkAddress = info.NetworkAddress
+	result.BroadcastAddress = info.BroadcastIP
+	result.SubnetMask = info.SubnetMask
+	result.WildcardMask = uint32ToAddr(calcMasks(prefix).WildcardMask)
+	result.TotalIPs = new(big.Int).SetUint64(uint64(info.TotalIP))
+
+	if c.provider != "" {
+		reserved, err := providerReserved(prefix, info, c.provider)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Reserved = reserved
+		setUsableRange(&result, func(addr netip.Addr) bool {
+			for _, r := range reserved {
+				if r.Address == addr {
+					return true
+				}
+			}
+			return false
+		})
+		return result, nil
+	}
+
+	setUsableRange(&result, func(addr netip.Addr) bool {
+		switch {
+		case prefix.Bits() == 32:
+			return false
+		case prefix.Bits() == 31:
+			return !c.rfc3021
+		default:
+			return addr == info.NetworkAddress || addr == info.BroadcastIP
+		}
+	})
+	return result, nil
+}
+
+// setUsableRange fills in the usable count and range of an IPv4 result. The
+// addresses excluded by reserved are always at the edges of the subnet.
+func setUsableRange(result *Result, reserved func(netip.Addr) bool) {
+	first, last := result.NetworkAddress, result.BroadcastAddress
+	excluded := int64(0)
+	for reserved(first) && first != last {
+		excluded++
+		first = first.Next()
+	}
+	for reserved(last) && last != first {
+		excluded++
+		last = last.Prev()
+	}
+	if reserved(first) {
+		excluded++
+		first, last = netip.Addr{}, netip.Addr{}
+	}
+
+	result.FirstUsable, result.LastUsable = first, last
+	result.UsableIPs = new(big.Int).Sub(result.TotalIPs, big.NewInt(excluded))
+}
@@ -0,0 +1,177 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleCalculator_Calculate() {
+	calc := NewCalculator(WithProvider(ProviderAWS))
+	result, _ := calc.Calculate(netip.MustParsePrefix("10.0.1.0/24"))
+	fmt.Println(result.FirstUsable, result.LastUsable, result.UsableIPs)
+	// Output: 10.0.1.4 10.0.1.254 251
+}
+
+func TestCalculator_IPv4(t *testing.T) {
+	result, err := NewCalculator().Calculate(netip.MustParsePrefix("172.16.38.94/27"))
+	require.NoError(t, err)
+
+	assert.Equal(t, Result{
+		Input:            netip.MustParsePrefix("172.16.38.94/27"),
+		Prefix:           netip.MustParsePrefix("172.16.38.64/27"),
+		NetworkAddress:   netip.MustParseAddr("172.16.38.64"),
+		BroadcastAddress: netip.MustParseAddr("172.16.38.95"),
+		SubnetMask:       netip.MustParseAddr("255.255.255.224"),
+		WildcardMask:     netip.MustParseAddr("0.0.0.31"),
+		FirstUsable:      netip.MustParseAddr("172.16.38.65"),
+		LastUsable:       netip.MustParseAddr("172.16.38.94"),
+		TotalIPs:         big.NewInt(32),
+		UsableIPs:        big.NewInt(30),
+	}, result)
+	assert.True(t, result.Normalized())
+}
+
+func TestCalculator_RFC3021(t *testing.T) {
+	p2p := netip.MustParsePrefix("192.0.2.0/31")
+
+	result, err := NewCalculator().Calculate(p2p)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), result.UsableIPs)
+	assert.Equal(t, netip.MustParseAddr("192.0.2.0"), result.FirstUsable)
+	assert.Equal(t, netip.MustParseAddr("192.0.2.1"), result.LastUsable)
+
+	result, err = NewCalculator(WithRFC3021(false)).Calculate(p2p)
+	require.NoError(t, err)
+	assert.Zero(t, result.UsableIPs.Sign())
+	assert.False(t, result.FirstUsable.IsValid())
+	assert.False(t, result.LastUsable.IsValid())
+
+	result, err = NewCalculator(WithRFC3021(false)).Calculate(netip.MustParsePrefix("192.0.2.1/32"))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), result.UsableIPs)
+	assert.Equal(t, result.FirstUsable, result.LastUsable)
+}
+
+func TestCalculator_Provider(t *testing.T) {
+	result, err := NewCalculator(WithProvider(ProviderGCP)).Calculate(netip.MustParsePrefix("10.128.0.0/29"))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(4), result.UsableIPs)
+	assert.Equal(t, netip.MustParseAddr("10.128.0.2"), result.FirstUsable)
+	assert.Equal(t, netip.MustParseAddr("10.128.0.5"), result.LastUsable)
+	assert.Len(t, result.Reserved, 4)
+
+	_, err = NewCalculator(WithProvider("oracle")).Calculate(netip.MustParsePrefix("10.0.0.0/24"))
+	assert.EqualError(t, err, `unknown provider "oracle"`)
+
+	_, err = NewCalculator(WithProvider(ProviderAWS), WithIPv6Policy(IPv6Allow)).Calculate(netip.MustParsePrefix("2001:db8::/64"))
+	assert.EqualError(t, err, "aws reservations only apply to IPv4 subnets")
+}
+
+func TestCalculator_Strict(t *testing.T) {
+	calc := NewCalculator(WithStrict(true))
+
+	_, err := calc.Calculate(netip.MustParsePrefix("10.0.0.1/8"))
+	var hostBits *HostBitsError
+	require.ErrorAs(t, err, &hostBits)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), hostBits.Canonical)
+
+	result, err := calc.Calculate(netip.MustParsePrefix("10.0.0.0/8"))
+	require.NoError(t, err)
+	assert.False(t, result.Normalized())
+}
+
+func TestCalculator_IPv6(t *testing.T) {
+	_, err := NewCalculator().Calculate(netip.MustParsePrefix("2001:db8::/64"))
+	assert.EqualError(t, err, "IPv6 not supported yet")
+
+	result, err := NewCalculator(WithIPv6Policy(IPv6Allow)).Calculate(netip.MustParsePrefix("2001:db8::1/64"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("2001:db8::/64"), result.Prefix)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::"), result.FirstUsable)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::ffff:ffff:ffff:ffff"), result.LastUsable)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 64), result.UsableIPs)
+	assert.False(t, result.BroadcastAddress.IsValid())
+	assert.False(t, result.SubnetMask.IsValid())
+	require.NotNil(t, result.IPv6)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::1"), result.IPv6.Address)
+	assert.Equal(t, "2001:0db8:0000:0000:0000:0000:0000:0001", result.IPv6.Expanded)
+
+	info := result.SubnetInfo()
+	assert.Equal(t, uint(0), info.TotalIP)
+
+	result, err = NewCalculator(WithIPv6Policy(IPv6Allow)).Calculate(netip.MustParsePrefix("2001:db8::/100"))
+	require.NoError(t, err)
+	assert.Equal(t, uint(1<<28), result.SubnetInfo().TotalIP)
+}
+
+func TestCalculator_Multicast(t *testing.T) {
+	result, err := NewCalculator().Calculate(netip.MustParsePrefix("224.0.0.251/32"))
+	require.NoError(t, err)
+	require.NotNil(t, result.Multicast)
+	assert.Equal(t, MulticastLinkLocal, result.Multicast.Scope)
+	assert.Equal(t, "01:00:5e:00:00:fb", result.Multicast.MAC.String())
+	assert.Len(t, result.Multicast.SharedMAC, 31)
+
+	ranges := []struct {
+		prefix string
+		scope  MulticastScope
+		ssm    bool
+		glop   bool
+		glopAS uint32
+	}{
+		{"232.0.0.0/8", MulticastGlobal, true, false, 0},
+		{"224.0.0.0/24", MulticastLinkLocal, false, false, 0},
+		{"224.0.0.0/4", MulticastMixed, false, false, 0},
+		{"232.0.0.0/7", MulticastGlobal, false, false, 0},
+		{"233.0.0.0/8", MulticastGlobal, false, false, 0},
+		{"233.22.30.0/24", MulticastGlobal, false, true, 5662},
+		{"233.22.0.0/16", MulticastGlobal, false, true, 0},
+		{"239.0.0.0/8", MulticastMixed, false, false, 0},
+		{"239.255.0.0/16", MulticastSiteLocal, false, false, 0},
+		{"224.0.0.0/3", MulticastMixed, false, false, 0},
+		{"ff00::/8", MulticastMixed, false, false, 0},
+		{"ff02::/16", MulticastLinkLocal, false, false, 0},
+		{"ff3e::/96", MulticastGlobal, true, false, 0},
+		{"ff3e::/32", MulticastGlobal, false, false, 0},
+	}
+	for _, tt := range ranges {
+		t.Run(tt.prefix, func(t *testing.T) {
+			result, err := NewCalculator(WithIPv6Policy(IPv6Allow)).Calculate(netip.MustParsePrefix(tt.prefix))
+			require.NoError(t, err)
+			require.NotNil(t, result.Multicast)
+			assert.Equal(t, tt.scope, result.Multicast.Scope)
+			assert.Equal(t, tt.ssm, result.Multicast.SSM)
+			assert.Equal(t, tt.glop, result.Multicast.GLOP)
+			assert.Equal(t, tt.glopAS, result.Multicast.GLOPAS)
+			assert.Nil(t, result.Multicast.MAC)
+			assert.Empty(t, result.Multicast.SharedMAC)
+		})
+	}
+
+	result, err = NewCalculator().Calculate(netip.MustParsePrefix("10.0.0.0/8"))
+	require.NoError(t, err)
+	assert.Nil(t, result.Multicast)
+}
+
+func TestCalculator_Annotator(t *testing.T) {
+	var trie AnnotationTrie
+	trie.Insert(netip.MustParsePrefix("193.0.0.0/21"), Annotation{ASN: 3333, Country: "NL"})
+
+	result, err := NewCalculator(WithAnnotator(&trie)).Calculate(netip.MustParsePrefix("193.0.0.0/24"))
+	require.NoError(t, err)
+	assert.Equal(t, &Annotation{Network: netip.MustParsePrefix("193.0.0.0/21"), ASN: 3333, Country: "NL"}, result.Annotation)
+
+	result, err = NewCalculator(WithAnnotator(&trie)).Calculate(netip.MustParsePrefix("10.0.0.0/8"))
+	require.NoError(t, err)
+	assert.Nil(t, result.Annotation)
+}
+
+func TestCalculator_Invalid(t *testing.T) {
+	_, err := NewCalculator().Calculate(netip.Prefix{})
+	assert.EqualError(t, err, "invalid prefix")
+}
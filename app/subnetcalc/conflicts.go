@@ -0,0 +1,113 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"slices"
+)
+
+// LabeledPrefix is a prefix with a human-readable name, such as a network
+// name or the file and line it came from.
+type LabeledPrefix struct {
+	Label  string       `json:"label" yaml:"label"`
+	Prefix netip.Prefix `json:"prefix" yaml:"prefix"`
+}
+
+// Conflict is a pair of overlapping prefixes. Overlap is the part they share,
+// which for CIDR prefixes is always the more specific of the two.
+type Conflict struct {
+	A       LabeledPrefix `json:"a" yaml:"a"`
+	B       LabeledPrefix `json:"b" yaml:"b"`
+	Overlap netip.Prefix  `json:"overlap" yaml:"overlap"`
+}
+
+// FindConflicts returns every pair of prefixes in the list that overlap,
+// ordered by the address of the first prefix in each pair. Invalid prefixes
+// are skipped.
+func FindConflicts(prefixes []LabeledPrefix) []Conflict {
+	sorted := sortedLabeledPrefixes(prefixes)
+	var conflicts []Conflict
+	for i, a := range sorted {
+		last := lastAddr(a.Prefix)
+		for _, b := range sorted[i+1:] {
+			if b.Prefix.Addr().Compare(last) > 0 {
+				break
+			}
+			conflicts = append(conflicts, newConflict(a, b))
+		}
+	}
+	return conflicts
+}
+
+// FindConflictsBetween returns every pair of overlapping prefixes where one
+// comes from a and the other from b, with the prefix from a first. Pairs are
+// ordered by the address of that prefix. Overlaps within a single list are
+// not reported.
+func FindConflictsBetween(a, b []LabeledPrefix) []Conflict {
+	// Both lists are merged into one sorted list, remembering where each
+	// prefix came from, and swept as in FindConflicts.
+	type source struct {
+		LabeledPrefix
+		fromA bool
+	}
+	var merged []source
+	for _, p := range sortedLabeledPrefixes(a) {
+		merged = append(merged, source{p, true})
+	}
+	for _, p := range sortedLabeledPrefixes(b) {
+		merged = append(merged, source{p, false})
+	}
+	slices.SortStableFunc(merged, func(x, y source) int { return comparePrefixes(x.Prefix, y.Prefix) })
+
+	var conflicts []Conflict
+	for i, x := range merged {
+		last := lastAddr(x.Prefix)
+		for _, y := range merged[i+1:] {
+			if y.Prefix.Addr().Compare(last) > 0 {
+				break
+			}
+			switch {
+			case x.fromA && !y.fromA:
+				conflicts = append(conflicts, newConflict(x.LabeledPrefix, y.LabeledPrefix))
+			case !x.fromA && y.fromA:
+				conflicts = append(conflicts, newConflict(y.LabeledPrefix, x.LabeledPrefix))
+			}
+		}
+	}
+	slices.SortStableFunc(conflicts, func(x, y Conflict) int {
+		if c := comparePrefixes(x.A.Prefix, y.A.Prefix); c != 0 {
+			return c
+		}
+		return comparePrefixes(x.B.Prefix, y.B.Prefix)
+	})
+	return conflicts
+}
+
+func newConflict(a, b LabeledPrefix) Conflict {
+	overlap := a.Prefix
+	if b.Prefix.Bits() > a.Prefix.Bits() {
+		overlap = b.Prefix
+	}
+	return Conflict{A: a, B: b, Overlap: overlap}
+}
+
+// sortedLabeledPrefixes returns the valid prefixes masked and sorted by
+// address, with shorter prefixes first when the addresses are equal.
+func sortedLabeledPrefixes(prefixes []LabeledPrefix) []LabeledPrefix {
+	sorted := make([]LabeledPrefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		if p.Prefix.IsValid() {
+			sorted = append(sorted, LabeledPrefix{Label: p.Label, Prefix: p.Prefix.Masked()})
+		}
+	}
+	slices.SortStableFunc(sorted, func(a, b LabeledPrefix) int { return comparePrefixes(a.Prefix, b.Prefix) })
+	return sorted
+}
+
+// comparePrefixes orders prefixes by address, with shorter prefixes first
+// when the addresses are equal.
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
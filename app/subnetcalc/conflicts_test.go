@@ -0,0 +1,105 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func labeled(label, prefix string) LabeledPrefix {
+	return LabeledPrefix{Label: label, Prefix: netip.MustParsePrefix(prefix)}
+}
+
+func conflictStrings(conflicts []Conflict) []string {
+	strs := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		strs = append(strs, fmt.Sprintf("%s %s / %s %s = %s", c.A.Label, c.A.Prefix, c.B.Label, c.B.Prefix, c.Overlap))
+	}
+	return strs
+}
+
+func ExampleFindConflicts() {
+	conflicts := FindConflicts([]LabeledPrefix{
+		{Label: "bridge", Prefix: netip.MustParsePrefix("172.17.0.0/16")},
+		{Label: "vpn", Prefix: netip.MustParsePrefix("172.16.0.0/12")},
+		{Label: "office", Prefix: netip.MustParsePrefix("192.168.0.0/24")},
+	})
+	for _, c := range conflicts {
+		fmt.Println(c.A.Label, c.B.Label, c.Overlap)
+	}
+	// Output: vpn bridge 172.17.0.0/16
+}
+
+func TestFindConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []LabeledPrefix
+		want  []string
+	}{
+		{"no prefixes", nil, []string{}},
+		{
+			"disjoint",
+			[]LabeledPrefix{labeled("a", "10.0.0.0/24"), labeled("b", "10.0.1.0/24")},
+			[]string{},
+		},
+		{
+			"identical",
+			[]LabeledPrefix{labeled("a", "10.0.0.0/24"), labeled("b", "10.0.0.0/24")},
+			[]string{"a 10.0.0.0/24 / b 10.0.0.0/24 = 10.0.0.0/24"},
+		},
+		{
+			"nested chain",
+			[]LabeledPrefix{labeled("host", "10.1.2.3/32"), labeled("site", "10.1.0.0/16"), labeled("corp", "10.0.0.0/8")},
+			[]string{
+				"corp 10.0.0.0/8 / site 10.1.0.0/16 = 10.1.0.0/16",
+				"corp 10.0.0.0/8 / host 10.1.2.3/32 = 10.1.2.3/32",
+				"site 10.1.0.0/16 / host 10.1.2.3/32 = 10.1.2.3/32",
+			},
+		},
+		{
+			"host bits are masked",
+			[]LabeledPrefix{labeled("a", "192.168.1.77/24"), labeled("b", "192.168.1.128/25")},
+			[]string{"a 192.168.1.0/24 / b 192.168.1.128/25 = 192.168.1.128/25"},
+		},
+		{
+			"families do not conflict",
+			[]LabeledPrefix{labeled("v4", "0.0.0.0/0"), labeled("v6", "::/0")},
+			[]string{},
+		},
+		{
+			"invalid skipped",
+			[]LabeledPrefix{{Label: "bad"}, labeled("a", "10.0.0.0/8")},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, conflictStrings(FindConflicts(tt.input)))
+		})
+	}
+}
+
+func TestFindConflictsBetween(t *testing.T) {
+	docker := []LabeledPrefix{labeled("bridge", "172.17.0.0/16"), labeled("pool", "172.16.0.0/12"), labeled("app", "192.168.0.0/20")}
+	corp := []LabeledPrefix{labeled("vpn", "172.18.0.0/16"), labeled("lab", "10.0.0.0/8")}
+
+	got := FindConflictsBetween(docker, corp)
+	assert.Equal(t, []string{"pool 172.16.0.0/12 / vpn 172.18.0.0/16 = 172.18.0.0/16"}, conflictStrings(got))
+}
+
+func TestFindConflictsBetween_Order(t *testing.T) {
+	a := []LabeledPrefix{labeled("host", "10.1.2.3/32"), labeled("site", "10.1.0.0/16"), labeled("v6", "2001:db8::/48")}
+	b := []LabeledPrefix{labeled("all", "10.0.0.0/8"), labeled("lan", "10.1.2.0/24"), labeled("other", "10.2.0.0/16"), labeled("doc", "2001:db8::/32")}
+
+	got := FindConflictsBetween(a, b)
+	assert.Equal(t, []string{
+		"site 10.1.0.0/16 / all 10.0.0.0/8 = 10.1.0.0/16",
+		"site 10.1.0.0/16 / lan 10.1.2.0/24 = 10.1.2.0/24",
+		"host 10.1.2.3/32 / all 10.0.0.0/8 = 10.1.2.3/32",
+		"host 10.1.2.3/32 / lan 10.1.2.0/24 = 10.1.2.3/32",
+		"v6 2001:db8::/48 / doc 2001:db8::/32 = 2001:db8::/48",
+	}, conflictStrings(got))
+}
@@ -0,0 +1,98 @@
+package subnetcalc
+
+import "net/netip"
+
+// ChangeKind classifies how a subnet changed between two address plans.
+type ChangeKind string
+
+// Kinds of plan changes.
+const (
+	ChangeAdded      ChangeKind = "added"
+	ChangeRemoved    ChangeKind = "removed"
+	ChangeGrown      ChangeKind = "grown"
+	ChangeShrunk     ChangeKind = "shrunk"
+	ChangeRenumbered ChangeKind = "renumbered"
+)
+
+// PlanChange is one difference between two address plans. Old is unset for
+// added subnets and New is unset for removed ones.
+type PlanChange struct {
+	Kind  ChangeKind   `json:"kind" yaml:"kind"`
+	Label string       `json:"label" yaml:"label"`
+	Old   netip.Prefix `json:"old" yaml:"old"`
+	New   netip.Prefix `json:"new" yaml:"new"`
+}
+
+// PlanDiff is the result of DiffPlans.
+type PlanDiff struct {
+	Changes     []PlanChange `json:"changes" yaml:"changes"`
+	NewOverlaps []Conflict   `json:"new_overlaps" yaml:"new_overlaps"`
+}
+
+// DiffPlans compares two lists of labeled prefixes.
+//
+// Subnets are matched by label, or by prefix when the label is empty. A
+// matched subnet whose new prefix contains the old one has grown, one whose
+// new prefix is inside the old one has shrunk, and any other move is a
+// renumbering. NewOverlaps lists the overlaps in newPlan that oldPlan did not
+// already have.
+func DiffPlans(oldPlan, newPlan []LabeledPrefix) PlanDiff {
+	pending := make(map[string][]LabeledPrefix)
+	for _, p := range newPlan {
+		key := planKey(p)
+		pending[key] = append(pending[key], p)
+	}
+
+	var diff PlanDiff
+	for _, o := range oldPlan {
+		key := planKey(o)
+		candidates := pending[key]
+		if len(candidates) == 0 {
+			diff.Changes = append(diff.Changes, PlanChange{Kind: ChangeRemoved, Label: o.Label, Old: o.Prefix.Masked()})
+			continue
+		}
+		n := candidates[0]
+		pending[key] = candidates[1:]
+
+		oldPrefix, newPrefix := o.Prefix.Masked(), n.Prefix.Masked()
+		change := PlanChange{Label: o.Label, Old: oldPrefix, New: newPrefix}
+		switch {
+		case oldPrefix == newPrefix:
+			continue
+		case newPrefix.Bits() < oldPrefix.Bits() && newPrefix.Contains(oldPrefix.Addr()):
+			change.Kind = ChangeGrown
+		case newPrefix.Bits() > oldPrefix.Bits() && oldPrefix.Contains(newPrefix.Addr()):
+			change.Kind = ChangeShrunk
+		default:
+			change.Kind = ChangeRenumbered
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	for _, n := range newPlan {
+		key := planKey(n)
+		for _, p := range pending[key] {
+			diff.Changes = append(diff.Changes, PlanChange{Kind: ChangeAdded, Label: p.Label, New: p.Prefix.Masked()})
+		}
+		delete(pending, key)
+	}
+
+	existing := make(map[[2]LabeledPrefix]bool)
+	for _, c := range FindConflicts(oldPlan) {
+		existing[[2]LabeledPrefix{c.A, c.B}] = true
+		existing[[2]LabeledPrefix{c.B, c.A}] = true
+	}
+	for _, c := range FindConflicts(newPlan) {
+		if !existing[[2]LabeledPrefix{c.A, c.B}] {
+			diff.NewOverlaps = append(diff.NewOverlaps, c)
+		}
+	}
+	return diff
+}
+
+func planKey(p LabeledPrefix) string {
+	if p.Label != "" {
+		return "label:" + p.Label
+	}
+	return "prefix:" + p.Prefix.Masked().String()
+}
@@ -0,0 +1,96 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleDiffPlans() {
+	oldPlan := []LabeledPrefix{
+		{Label: "web", Prefix: netip.MustParsePrefix("10.0.1.0/24")},
+		{Label: "db", Prefix: netip.MustParsePrefix("10.0.2.0/24")},
+	}
+	newPlan := []LabeledPrefix{
+		{Label: "web", Prefix: netip.MustParsePrefix("10.0.0.0/23")},
+		{Label: "db", Prefix: netip.MustParsePrefix("10.0.2.0/24")},
+	}
+	for _, c := range DiffPlans(oldPlan, newPlan).Changes {
+		fmt.Println(c.Kind, c.Label, c.Old, "->", c.New)
+	}
+	// Output: grown web 10.0.1.0/24 -> 10.0.0.0/23
+}
+
+func TestDiffPlans_Changes(t *testing.T) {
+	oldPlan := []LabeledPrefix{
+		labeled("web", "10.0.0.0/24"),
+		labeled("db", "10.0.1.0/24"),
+		labeled("cache", "10.0.2.0/24"),
+		labeled("legacy", "10.0.3.0/24"),
+		labeled("mgmt", "10.0.4.0/24"),
+		{Prefix: netip.MustParsePrefix("192.168.0.0/24")},
+	}
+	newPlan := []LabeledPrefix{
+		labeled("web", "10.0.0.0/23"),
+		labeled("db", "10.0.1.128/25"),
+		labeled("cache", "10.0.12.0/24"),
+		labeled("mgmt", "10.0.4.0/24"),
+		labeled("batch", "10.0.5.0/24"),
+		{Prefix: netip.MustParsePrefix("192.168.0.0/24")},
+		{Prefix: netip.MustParsePrefix("192.168.1.0/24")},
+	}
+
+	want := []PlanChange{
+		{Kind: ChangeGrown, Label: "web", Old: netip.MustParsePrefix("10.0.0.0/24"), New: netip.MustParsePrefix("10.0.0.0/23")},
+		{Kind: ChangeShrunk, Label: "db", Old: netip.MustParsePrefix("10.0.1.0/24"), New: netip.MustParsePrefix("10.0.1.128/25")},
+		{Kind: ChangeRenumbered, Label: "cache", Old: netip.MustParsePrefix("10.0.2.0/24"), New: netip.MustParsePrefix("10.0.12.0/24")},
+		{Kind: ChangeRemoved, Label: "legacy", Old: netip.MustParsePrefix("10.0.3.0/24")},
+		{Kind: ChangeAdded, Label: "batch", New: netip.MustParsePrefix("10.0.5.0/24")},
+		{Kind: ChangeAdded, New: netip.MustParsePrefix("192.168.1.0/24")},
+	}
+
+	diff := DiffPlans(oldPlan, newPlan)
+	assert.Equal(t, want, diff.Changes)
+	assert.Equal(t, []string{"web 10.0.0.0/23 / db 10.0.1.128/25 = 10.0.1.128/25"}, conflictStrings(diff.NewOverlaps))
+}
+
+func TestDiffPlans_ExistingOverlapsNotReported(t *testing.T) {
+	oldPlan := []LabeledPrefix{labeled("site", "10.0.0.0/16"), labeled("vpc", "10.0.1.0/24")}
+	newPlan := []LabeledPrefix{labeled("site", "10.0.0.0/16"), labeled("vpc", "10.0.1.0/24"), labeled("lab", "10.0.1.128/25")}
+
+	diff := DiffPlans(oldPlan, newPlan)
+	assert.Equal(t, []PlanChange{{Kind: ChangeAdded, Label: "lab", New: netip.MustParsePrefix("10.0.1.128/25")}}, diff.Changes)
+	assert.Equal(t, []string{
+		"site 10.0.0.0/16 / lab 10.0.1.128/25 = 10.0.1.128/25",
+		"vpc 10.0.1.0/24 / lab 10.0.1.128/25 = 10.0.1.128/25",
+	}, conflictStrings(diff.NewOverlaps))
+}
+
+func TestDiffPlans_Identical(t *testing.T) {
+	plan := []LabeledPrefix{labeled("a", "10.0.0.0/24"), labeled("b", "10.0.0.5/24")}
+	diff := DiffPlans(plan, plan)
+	assert.Empty(t, diff.Changes)
+	assert.Empty(t, diff.NewOverlaps)
+}
+
+func TestDiffPlans_DuplicateLabels(t *testing.T) {
+	oldPlan := []LabeledPrefix{labeled("spare", "10.0.0.0/24"), labeled("spare", "10.0.1.0/24")}
+	newPlan := []LabeledPrefix{labeled("spare", "10.0.0.0/24")}
+
+	diff := DiffPlans(oldPlan, newPlan)
+	assert.Equal(t, []PlanChange{{Kind: ChangeRemoved, Label: "spare", Old: netip.MustParsePrefix("10.0.1.0/24")}}, diff.Changes)
+}
+
+func TestDiffPlans_HostBitsMasked(t *testing.T) {
+	oldPlan := []LabeledPrefix{labeled("gone", "10.0.0.5/24"), labeled("moved", "10.0.1.5/24")}
+	newPlan := []LabeledPrefix{labeled("moved", "10.0.2.5/24"), labeled("new", "10.0.3.5/24")}
+
+	diff := DiffPlans(oldPlan, newPlan)
+	assert.Equal(t, []PlanChange{
+		{Kind: ChangeRemoved, Label: "gone", Old: netip.MustParsePrefix("10.0.0.0/24")},
+		{Kind: ChangeRenumbered, Label: "moved", Old: netip.MustParsePrefix("10.0.1.0/24"), New: netip.MustParsePrefix("10.0.2.0/24")},
+		{Kind: ChangeAdded, Label: "new", New: netip.MustParsePrefix("10.0.3.0/24")},
+	}, diff.Changes)
+}
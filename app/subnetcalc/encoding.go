@@ -0,0 +1,382 @@
+package subnetcalc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/netip"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// OutputFormat selects how results are written by Encode.
+type OutputFormat string
+
+// Supported output formats. Text is the human-readable layout printed by the
+// command line and is not handled by Encode.
+const (
+	OutputText OutputFormat = "text"
+	OutputJSON OutputFormat = "json"
+	OutputYAML OutputFormat = "yaml"
+)
+
+// OutputFormats lists every output format.
+var OutputFormats = []OutputFormat{OutputText, OutputJSON, OutputYAML}
+
+// Encode writes v to w as indented JSON or as YAML.
+func Encode(w io.Writer, v any, format OutputFormat) error {
+	switch format {
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported encoding %q", format)
+	}
+}
+
+// addressCountValue encodes an address count as a plain integer in both JSON
+// and YAML. A bare *big.Int is written as a quoted string in YAML.
+type addressCountValue big.Int
+
+func (c *addressCountValue) MarshalJSON() ([]byte, error) {
+	return (*big.Int)(c).MarshalJSON()
+}
+
+func (c *addressCountValue) UnmarshalJSON(data []byte) error {
+	return (*big.Int)(c).UnmarshalJSON(data)
+}
+
+func (c *addressCountValue) MarshalYAML() (any, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: (*big.Int)(c).String()}, nil
+}
+
+func (c *addressCountValue) UnmarshalYAML(node *yaml.Node) error {
+	if _, ok := (*big.Int)(c).SetString(node.Value, 10); !ok {
+		return fmt.Errorf("invalid address count %q", node.Value)
+	}
+	return nil
+}
+
+// optionalAddr returns the text form of addr, or "" when it is unset so the
+// field can be omitted.
+func optionalAddr(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+	return addr.String()
+}
+
+func parseOptionalAddr(s string) (netip.Addr, error) {
+	if s == "" {
+		return netip.Addr{}, nil
+	}
+	return netip.ParseAddr(s)
+}
+
+// resultJSON is the encoded form of Result. Addresses that are only set for
+// some results are omitted when unset.
+type resultJSON struct {
+	Input            netip.Prefix       `json:"input" yaml:"input"`
+	Prefix           netip.Prefix       `json:"prefix" yaml:"prefix"`
+	NetworkAddress   netip.Addr         `json:"network_address" yaml:"network_address"`
+	BroadcastAddress string             `json:"broadcast_address,omitempty" yaml:"broadcast_address,omitempty"`
+	SubnetMask       string             `json:"subnet_mask,omitempty" yaml:"subnet_mask,omitempty"`
+	WildcardMask     string             `json:"wildcard_mask,omitempty" yaml:"wildcard_mask,omitempty"`
+	FirstUsable      string             `json:"first_usable,omitempty" yaml:"first_usable,omitempty"`
+	LastUsable       string             `json:"last_usable,omitempty" yaml:"last_usable,omitempty"`
+	TotalIPs         *addressCountValue `json:"total_ips" yaml:"total_ips"`
+	UsableIPs        *addressCountValue `json:"usable_ips" yaml:"usable_ips"`
+	Reserved         []ReservedAddress  `json:"reserved,omitempty" yaml:"reserved,omitempty"`
+	IPv6             *IPv6Info          `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+	Multicast        *MulticastInfo     `json:"multicast,omitempty" yaml:"multicast,omitempty"`
+	Annotation       *Annotation        `json:"annotation,omitempty" yaml:"annotation,omitempty"`
+}
+
+func (r Result) encoded() resultJSON {
+	return resultJSON{
+		Input:            r.Input,
+		Prefix:           r.Prefix,
+		NetworkAddress:   r.NetworkAddress,
+		BroadcastAddress: optionalAddr(r.BroadcastAddress),
+		SubnetMask:       optionalAddr(r.SubnetMask),
+		WildcardMask:     optionalAddr(r.WildcardMask),
+		FirstUsable:      optionalAddr(r.FirstUsable),
+		LastUsable:       optionalAddr(r.LastUsable),
+		TotalIPs:         (*addressCountValue)(r.TotalIPs),
+		UsableIPs:        (*addressCountValue)(r.UsableIPs),
+		Reserved:         r.Reserved,
+		IPv6:             r.IPv6,
+		Multicast:        r.Multicast,
+		Annotation:       r.Annotation,
+	}
+}
+
+func (r *Result) decode(e resultJSON) error {
+	*r = Result{
+		Input:          e.Input,
+		Prefix:         e.Prefix,
+		NetworkAddress: e.NetworkAddress,
+		TotalIPs:       (*big.Int)(e.TotalIPs),
+		UsableIPs:      (*big.Int)(e.UsableIPs),
+		Reserved:       e.Reserved,
+		IPv6:           e.IPv6,
+		Multicast:      e.Multicast,
+		Annotation:     e.Annotation,
+	}
+	for _, f := range []struct {
+		dst *netip.Addr
+		src string
+	}{
+		{&r.BroadcastAddress, e.BroadcastAddress},
+		{&r.SubnetMask, e.SubnetMask},
+		{&r.WildcardMask, e.WildcardMask},
+		{&r.FirstUsable, e.FirstUsable},
+		{&r.LastUsable, e.LastUsable},
+	} {
+		addr, err := parseOptionalAddr(f.src)
+		if err != nil {
+			return err
+		}
+		*f.dst = addr
+	}
+	return nil
+}
+
+// MarshalJSON encodes r as an object with snake_case keys. Address counts are
+// JSON numbers, which may exceed 2^53 for IPv6 prefixes.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.encoded())
+}
+
+// UnmarshalJSON decodes the encoding produced by MarshalJSON.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var e resultJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	return r.decode(e)
+}
+
+// MarshalYAML encodes r with the same keys as MarshalJSON.
+func (r Result) MarshalYAML() (any, error) {
+	return r.encoded(), nil
+}
+
+// UnmarshalYAML decodes the encoding produced by MarshalYAML.
+func (r *Result) UnmarshalYAML(node *yaml.Node) error {
+	var e resultJSON
+	if err := node.Decode(&e); err != nil {
+		return err
+	}
+	return r.decode(e)
+}
+
+// ipv6InfoJSON is the encoded form of IPv6Info.
+type ipv6InfoJSON struct {
+	Address    netip.Addr         `json:"address" yaml:"address"`
+	Zone       string             `json:"zone,omitempty" yaml:"zone,omitempty"`
+	Prefix     netip.Prefix       `json:"prefix" yaml:"prefix"`
+	Compressed string             `json:"compressed" yaml:"compressed"`
+	Expanded   string             `json:"expanded" yaml:"expanded"`
+	Reverse    string             `json:"reverse" yaml:"reverse"`
+	Binary     string             `json:"binary" yaml:"binary"`
+	TotalIP    *addressCountValue `json:"total_ips" yaml:"total_ips"`
+}
+
+func (i IPv6Info) encoded() ipv6InfoJSON {
+	return ipv6InfoJSON{
+		Address:    i.Address,
+		Zone:       i.Zone,
+		Prefix:     i.Prefix,
+		Compressed: i.Compressed,
+		Expanded:   i.Expanded,
+		Reverse:    i.Reverse,
+		Binary:     i.Binary,
+		TotalIP:    (*addressCountValue)(i.TotalIP),
+	}
+}
+
+func (i *IPv6Info) decode(e ipv6InfoJSON) {
+	*i = IPv6Info{
+		Address:    e.Address,
+		Zone:       e.Zone,
+		Prefix:     e.Prefix,
+		Compressed: e.Compressed,
+		Expanded:   e.Expanded,
+		Reverse:    e.Reverse,
+		Binary:     e.Binary,
+		TotalIP:    (*big.Int)(e.TotalIP),
+	}
+}
+
+// MarshalJSON encodes i as an object with snake_case keys.
+func (i IPv6Info) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.encoded())
+}
+
+// UnmarshalJSON decodes the encoding produced by MarshalJSON.
+func (i *IPv6Info) UnmarshalJSON(data []byte) error {
+	var e ipv6InfoJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	i.decode(e)
+	return nil
+}
+
+// MarshalYAML encodes i with the same keys as MarshalJSON.
+func (i IPv6Info) MarshalYAML() (any, error) {
+	return i.encoded(), nil
+}
+
+// UnmarshalYAML decodes the encoding produced by MarshalYAML.
+func (i *IPv6Info) UnmarshalYAML(node *yaml.Node) error {
+	var e ipv6InfoJSON
+	if err := node.Decode(&e); err != nil {
+		return err
+	}
+	i.decode(e)
+	return nil
+}
+
+// splitResultJSON is the encoded form of SplitResult.
+type splitResultJSON struct {
+	Prefix   netip.Prefix       `json:"prefix" yaml:"prefix"`
+	Bits     int                `json:"bits" yaml:"bits"`
+	Count    *addressCountValue `json:"count" yaml:"count"`
+	Subnets  []netip.Prefix     `json:"subnets,omitempty" yaml:"subnets,omitempty"`
+	Warnings []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+func (s SplitResult) encoded() splitResultJSON {
+	return splitResultJSON{Prefix: s.Prefix, Bits: s.Bits, Count: (*addressCountValue)(s.Count), Subnets: s.Subnets, Warnings: s.Warnings}
+}
+
+func (s *SplitResult) decode(e splitResultJSON) {
+	*s = SplitResult{Prefix: e.Prefix, Bits: e.Bits, Count: (*big.Int)(e.Count), Subnets: e.Subnets, Warnings: e.Warnings}
+}
+
+// MarshalJSON encodes s as an object with snake_case keys.
+func (s SplitResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.encoded())
+}
+
+// UnmarshalJSON decodes the encoding produced by MarshalJSON.
+func (s *SplitResult) UnmarshalJSON(data []byte) error {
+	var e splitResultJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	s.decode(e)
+	return nil
+}
+
+// MarshalYAML encodes s with the same keys as MarshalJSON.
+func (s SplitResult) MarshalYAML() (any, error) {
+	return s.encoded(), nil
+}
+
+// UnmarshalYAML decodes the encoding produced by MarshalYAML.
+func (s *SplitResult) UnmarshalYAML(node *yaml.Node) error {
+	var e splitResultJSON
+	if err := node.Decode(&e); err != nil {
+		return err
+	}
+	s.decode(e)
+	return nil
+}
+
+// multicastInfoJSON is the encoded form of MulticastInfo, with the MAC
+// address in colon-separated text rather than as bytes.
+type multicastInfoJSON struct {
+	Address   netip.Addr     `json:"address" yaml:"address"`
+	Scope     MulticastScope `json:"scope" yaml:"scope"`
+	SSM       bool           `json:"ssm" yaml:"ssm"`
+	GLOP      bool           `json:"glop" yaml:"glop"`
+	GLOPAS    uint32         `json:"glop_as,omitempty" yaml:"glop_as,omitempty"`
+	MAC       string         `json:"mac,omitempty" yaml:"mac,omitempty"`
+	SharedMAC []netip.Addr   `json:"shared_mac,omitempty" yaml:"shared_mac,omitempty"`
+}
+
+func (m MulticastInfo) encoded() multicastInfoJSON {
+	e := multicastInfoJSON{Address: m.Address, Scope: m.Scope, SSM: m.SSM, GLOP: m.GLOP, GLOPAS: m.GLOPAS, SharedMAC: m.SharedMAC}
+	if m.MAC != nil {
+		e.MAC = m.MAC.String()
+	}
+	return e
+}
+
+func (m *MulticastInfo) decode(e multicastInfoJSON) error {
+	*m = MulticastInfo{Address: e.Address, Scope: e.Scope, SSM: e.SSM, GLOP: e.GLOP, GLOPAS: e.GLOPAS, SharedMAC: e.SharedMAC}
+	if e.MAC != "" {
+		mac, err := net.ParseMAC(e.MAC)
+		if err != nil {
+			return err
+		}
+		m.MAC = mac
+	}
+	return nil
+}
+
+// MarshalJSON encodes m as an object with snake_case keys.
+func (m MulticastInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.encoded())
+}
+
+// UnmarshalJSON decodes the encoding produced by MarshalJSON.
+func (m *MulticastInfo) UnmarshalJSON(data []byte) error {
+	var e multicastInfoJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	return m.decode(e)
+}
+
+// MarshalYAML encodes m with the same keys as MarshalJSON.
+func (m MulticastInfo) MarshalYAML() (any, error) {
+	return m.encoded(), nil
+}
+
+// UnmarshalYAML decodes the encoding produced by MarshalYAML.
+func (m *MulticastInfo) UnmarshalYAML(node *yaml.Node) error {
+	var e multicastInfoJSON
+	if err := node.Decode(&e); err != nil {
+		return err
+	}
+	return m.decode(e)
+}
+
+// MarshalText encodes w in the "address wildcard" form of String, so it is
+// a plain string in JSON and YAML.
+func (w Wildcard) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText parses the "address wildcard" form produced by MarshalText.
+func (w *Wildcard) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) != 2 {
+		return fmt.Errorf("invalid wildcard %q", text)
+	}
+	addr, err := netip.ParseAddr(fields[0])
+	if err != nil {
+		return err
+	}
+	mask, err := netip.ParseAddr(fields[1])
+	if err != nil {
+		return err
+	}
+	*w, err = NewWildcard(addr, mask)
+	return err
+}
@@ -0,0 +1,154 @@
+package subnetcalc
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compares got with testdata/name, rewriting the file instead
+// when the tests run with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func encodeGolden(t *testing.T, v any, format OutputFormat) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, v, format))
+	return buf.Bytes()
+}
+
+func encodingSamples(t *testing.T) map[string]any {
+	t.Helper()
+	ipv4, err := NewCalculator().Calculate(netip.MustParsePrefix("172.16.38.94/27"))
+	require.NoError(t, err)
+	aws, err := NewCalculator(WithProvider(ProviderAWS)).Calculate(netip.MustParsePrefix("10.0.1.0/28"))
+	require.NoError(t, err)
+	ipv6, err := NewCalculator(WithIPv6Policy(IPv6Allow)).Calculate(netip.MustParsePrefix("2001:db8::/32"))
+	require.NoError(t, err)
+	group, err := NewCalculator().Calculate(netip.MustParsePrefix("224.0.0.251/32"))
+	require.NoError(t, err)
+	var trie AnnotationTrie
+	trie.Insert(netip.MustParsePrefix("193.0.0.0/21"), Annotation{ASN: 3333, Organization: "RIPE NCC", Country: "NL"})
+	annotated, err := NewCalculator(WithAnnotator(&trie)).Calculate(netip.MustParsePrefix("193.0.0.0/22"))
+	require.NoError(t, err)
+	info, err := CalcProviderSubnetInfo(netip.MustParsePrefix("10.0.1.0/28"), ProviderAzure)
+	require.NoError(t, err)
+	v6info, err := ParseIPv6("fe80::1%eth0/64")
+	require.NoError(t, err)
+	split, err := SplitPrefix(netip.MustParsePrefix("2001:db8::/48"), 50, 2)
+	require.NoError(t, err)
+	multicast, err := AnalyzeMulticast(netip.MustParseAddr("233.1.2.3"))
+	require.NoError(t, err)
+	wildcard, err := NewWildcard(netip.MustParseAddr("10.0.0.0"), netip.MustParseAddr("0.0.255.0"))
+	require.NoError(t, err)
+	rdns, err := ReverseDNS(netip.MustParsePrefix("172.16.38.64/30"))
+	require.NoError(t, err)
+	plan, err := ParsePlan([]byte(validPlanYAML))
+	require.NoError(t, err)
+	k8s, err := PlanKubernetes(KubernetesNetworks{
+		NodeCIDR:         netip.MustParsePrefix("10.0.0.0/22"),
+		PodCIDR:          netip.MustParsePrefix("10.244.0.0/16"),
+		ServiceCIDR:      netip.MustParsePrefix("10.96.0.0/12"),
+		MaxPodsPerNode:   110,
+		NodeCIDRMaskSize: 24,
+	})
+	require.NoError(t, err)
+	records, err := ParseDelegatedStats(strings.NewReader(delegatedSample))
+	require.NoError(t, err)
+	summary, err := SummarizeDelegated(records, GroupByCountry)
+	require.NoError(t, err)
+	findings, err := LintPrefixList(strings.NewReader("10.0.0.1/24\n10.0.0.0/24\n"), LintOptions{})
+	require.NoError(t, err)
+
+	return map[string]any{
+		"result_ipv4":       ipv4,
+		"result_aws":        aws,
+		"result_ipv6":       ipv6,
+		"result_multicast":  group,
+		"result_annotated":  annotated,
+		"subnet_info":       info,
+		"ipv6_info_zoned":   v6info,
+		"split_result":      split,
+		"multicast_info":    multicast,
+		"wildcard":          wildcard,
+		"reverse_dns":       rdns,
+		"plan_report":       ValidatePlan(plan),
+		"plan_diff":         DiffPlans([]LabeledPrefix{labeled("web", "10.0.0.0/24")}, []LabeledPrefix{labeled("web", "10.0.0.0/23"), labeled("db", "10.0.1.0/24")}),
+		"conflicts":         FindConflicts([]LabeledPrefix{labeled("a", "10.0.0.0/16"), labeled("b", "10.0.4.0/24")}),
+		"kubernetes_plan":   k8s,
+		"delegated_summary": summary,
+		"lint_findings":     findings,
+		"tree":              BuildTree([]LabeledPrefix{labeled("site", "10.0.0.0/22"), labeled("web", "10.0.1.0/24")}),
+		"annotation":        Annotation{Network: netip.MustParsePrefix("193.0.0.0/21"), ASN: 3333, Organization: "RIPE NCC", Country: "NL"},
+	}
+}
+
+func TestEncode_Golden(t *testing.T) {
+	for name, v := range encodingSamples(t) {
+		t.Run(name, func(t *testing.T) {
+			assertGolden(t, name+".json", encodeGolden(t, v, OutputJSON))
+			assertGolden(t, name+".yaml", encodeGolden(t, v, OutputYAML))
+		})
+	}
+}
+
+// TestEncode_RoundTrip decodes every encoding into a new value of the
+// sample's type and checks that it encodes to the same document again.
+func TestEncode_RoundTrip(t *testing.T) {
+	for name, v := range encodingSamples(t) {
+		t.Run(name, func(t *testing.T) {
+			data := encodeGolden(t, v, OutputJSON)
+			fromJSON := reflect.New(reflect.TypeOf(v))
+			require.NoError(t, json.Unmarshal(data, fromJSON.Interface()))
+			assert.Equal(t, string(data), string(encodeGolden(t, fromJSON.Elem().Interface(), OutputJSON)))
+
+			data = encodeGolden(t, v, OutputYAML)
+			fromYAML := reflect.New(reflect.TypeOf(v))
+			require.NoError(t, yaml.Unmarshal(data, fromYAML.Interface()))
+			assert.Equal(t, string(data), string(encodeGolden(t, fromYAML.Elem().Interface(), OutputYAML)))
+		})
+	}
+}
+
+func TestEncode_SubnetInfoRoundTrip(t *testing.T) {
+	info, err := CalcProviderSubnetInfo(netip.MustParsePrefix("10.0.1.0/28"), ProviderAzure)
+	require.NoError(t, err)
+
+	var decoded SubnetInfo
+	require.NoError(t, json.Unmarshal(encodeGolden(t, info, OutputJSON), &decoded))
+	assert.Equal(t, info, decoded)
+	assert.Equal(t, info.UsableHosts(), decoded.UsableHosts())
+}
+
+func TestEncode_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.EqualError(t, Encode(&buf, SubnetInfo{}, OutputText), `unsupported encoding "text"`)
+}
+
+func TestResult_UnmarshalJSON_InvalidAddress(t *testing.T) {
+	var r Result
+	err := json.Unmarshal([]byte(`{"input":"10.0.0.0/8","prefix":"10.0.0.0/8","network_address":"10.0.0.0","broadcast_address":"bogus","total_ips":1,"usable_ips":1}`), &r)
+	assert.ErrorContains(t, err, "bogus")
+}
@@ -0,0 +1,75 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// linkLocalPrefix is the IPv6 link-local prefix used by LinkLocalAddress.
+var linkLocalPrefix = netip.MustParsePrefix("fe80::/64")
+
+// EUI64InterfaceID returns the modified EUI-64 interface identifier of a MAC
+// address (RFC 4291, appendix A). For a 48-bit MAC, ff:fe is inserted in the
+// middle; in both 48- and 64-bit identifiers the universal/local bit is
+// inverted.
+func EUI64InterfaceID(mac net.HardwareAddr) ([8]byte, error) {
+	var id [8]byte
+	switch len(mac) {
+	case 6:
+		copy(id[:3], mac[:3])
+		id[3], id[4] = 0xff, 0xfe
+		copy(id[5:], mac[3:])
+	case 8:
+		copy(id[:], mac)
+	default:
+		return id, fmt.Errorf("%s is not a 48- or 64-bit MAC address", mac)
+	}
+	id[0] ^= 0x02
+	return id, nil
+}
+
+// EUI64Address returns the address a SLAAC host with the given MAC address
+// configures in prefix: the first 64 bits of prefix followed by the EUI-64
+// interface identifier. SLAAC only works on /64 prefixes, so any other
+// length is rejected.
+func EUI64Address(prefix netip.Prefix, mac net.HardwareAddr) (netip.Addr, error) {
+	if !prefix.IsValid() {
+		return netip.Addr{}, errors.New("invalid prefix")
+	}
+	if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+		return netip.Addr{}, errors.New("EUI-64 addresses require an IPv6 prefix")
+	}
+	if prefix.Bits() != 64 {
+		return netip.Addr{}, fmt.Errorf("SLAAC requires a /64 prefix, got /%d", prefix.Bits())
+	}
+	id, err := EUI64InterfaceID(mac)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	b := prefix.Masked().Addr().As16()
+	copy(b[8:], id[:])
+	return netip.AddrFrom16(b), nil
+}
+
+// LinkLocalAddress returns the fe80::/64 link-local address derived from
+// a MAC address.
+func LinkLocalAddress(mac net.HardwareAddr) (netip.Addr, error) {
+	return EUI64Address(linkLocalPrefix, mac)
+}
+
+// MACFromEUI64 extracts the 48-bit MAC address embedded in an EUI-64 based
+// IPv6 address. It fails when the interface identifier was not derived from
+// a 48-bit MAC, for example privacy or manually assigned addresses.
+func MACFromEUI64(addr netip.Addr) (net.HardwareAddr, error) {
+	if !addr.Is6() || addr.Is4In6() {
+		return nil, fmt.Errorf("%s is not an IPv6 address", addr)
+	}
+	b := addr.As16()
+	if b[11] != 0xff || b[12] != 0xfe {
+		return nil, fmt.Errorf("%s does not contain an EUI-64 interface identifier", addr.WithZone(""))
+	}
+	mac := net.HardwareAddr{b[8] ^ 0x02, b[9], b[10], b[13], b[14], b[15]}
+	return mac, nil
+}
@@ -0,0 +1,76 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustMAC(t *testing.T, s string) net.HardwareAddr {
+	t.Helper()
+	mac, err := net.ParseMAC(s)
+	require.NoError(t, err)
+	return mac
+}
+
+func ExampleEUI64Address() {
+	mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+	addr, _ := EUI64Address(netip.MustParsePrefix("2001:db8:1:2::/64"), mac)
+	fmt.Println(addr)
+	// Output: 2001:db8:1:2:21a:2bff:fe3c:4d5e
+}
+
+func TestEUI64InterfaceID(t *testing.T) {
+	id, err := EUI64InterfaceID(mustMAC(t, "02:00:5e:10:00:01"))
+	require.NoError(t, err)
+	assert.Equal(t, [8]byte{0x00, 0x00, 0x5e, 0xff, 0xfe, 0x10, 0x00, 0x01}, id)
+
+	id, err = EUI64InterfaceID(mustMAC(t, "00:11:22:33:44:55:66:77"))
+	require.NoError(t, err)
+	assert.Equal(t, [8]byte{0x02, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77}, id)
+
+	_, err = EUI64InterfaceID(net.HardwareAddr{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestEUI64Address(t *testing.T) {
+	mac := mustMAC(t, "00:1a:2b:3c:4d:5e")
+
+	addr, err := EUI64Address(netip.MustParsePrefix("2001:db8:1:2:ffff::/64"), mac)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("2001:db8:1:2:21a:2bff:fe3c:4d5e"), addr)
+
+	_, err = EUI64Address(netip.MustParsePrefix("2001:db8::/48"), mac)
+	assert.EqualError(t, err, "SLAAC requires a /64 prefix, got /48")
+
+	_, err = EUI64Address(netip.MustParsePrefix("2001:db8::/96"), mac)
+	assert.EqualError(t, err, "SLAAC requires a /64 prefix, got /96")
+
+	_, err = EUI64Address(netip.MustParsePrefix("10.0.0.0/8"), mac)
+	assert.EqualError(t, err, "EUI-64 addresses require an IPv6 prefix")
+
+	_, err = EUI64Address(netip.Prefix{}, mac)
+	assert.EqualError(t, err, "invalid prefix")
+}
+
+func TestLinkLocalAddress(t *testing.T) {
+	addr, err := LinkLocalAddress(mustMAC(t, "52:54:00:12:34:56"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("fe80::5054:ff:fe12:3456"), addr)
+}
+
+func TestMACFromEUI64(t *testing.T) {
+	mac, err := MACFromEUI64(netip.MustParseAddr("fe80::5054:ff:fe12:3456%eth0"))
+	require.NoError(t, err)
+	assert.Equal(t, "52:54:00:12:34:56", mac.String())
+
+	_, err = MACFromEUI64(netip.MustParseAddr("2001:db8::1%eth0"))
+	assert.EqualError(t, err, "2001:db8::1 does not contain an EUI-64 interface identifier")
+
+	_, err = MACFromEUI64(netip.MustParseAddr("192.0.2.1"))
+	assert.EqualError(t, err, "192.0.2.1 is not an IPv6 address")
+}
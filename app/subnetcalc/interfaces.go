@@ -0,0 +1,65 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// InterfaceAddress is an address configured on a network interface. Address
+// keeps its host bits, as in "192.168.1.10/24".
+type InterfaceAddress struct {
+	Interface string       `json:"interface" yaml:"interface"`
+	Address   netip.Prefix `json:"address" yaml:"address"`
+}
+
+// InterfaceIssue is a problem found with an interface address.
+type InterfaceIssue struct {
+	Interface string       `json:"interface" yaml:"interface"`
+	Address   netip.Prefix `json:"address" yaml:"address"`
+	Problem   string       `json:"problem" yaml:"problem"`
+}
+
+// CheckInterfaceAddresses reports IPv4 addresses that are the network or
+// broadcast address of their own subnet, and addresses on different
+// interfaces whose subnets overlap. Link-local addresses are exempt from the
+// overlap check since every interface carries one.
+func CheckInterfaceAddresses(addrs []InterfaceAddress) []InterfaceIssue {
+	var issues []InterfaceIssue
+	var subnets []LabeledPrefix
+	owners := make(map[string]InterfaceAddress)
+
+	for _, a := range addrs {
+		if !a.Address.IsValid() {
+			continue
+		}
+		if a.Address.Addr().Is4() && a.Address.Bits() <= 30 {
+			info, err := CalcSubnetInfo(a.Address)
+			if err == nil {
+				switch a.Address.Addr() {
+				case info.NetworkAddress:
+					issues = append(issues, InterfaceIssue{a.Interface, a.Address, "address is the network address of " + a.Address.Masked().String()})
+				case info.BroadcastIP:
+					issues = append(issues, InterfaceIssue{a.Interface, a.Address, "address is the broadcast address of " + a.Address.Masked().String()})
+				}
+			}
+		}
+		if a.Address.Addr().IsLinkLocalUnicast() {
+			continue
+		}
+		label := a.Interface + " " + a.Address.String()
+		owners[label] = a
+		subnets = append(subnets, LabeledPrefix{Label: label, Prefix: a.Address})
+	}
+
+	for _, c := range FindConflicts(subnets) {
+		a, b := owners[c.A.Label], owners[c.B.Label]
+		if a.Interface == b.Interface {
+			continue
+		}
+		issues = append(issues,
+			InterfaceIssue{a.Interface, a.Address, fmt.Sprintf("subnet %s overlaps %s on %s", c.A.Prefix, b.Address, b.Interface)},
+			InterfaceIssue{b.Interface, b.Address, fmt.Sprintf("subnet %s overlaps %s on %s", c.B.Prefix, a.Address, a.Interface)},
+		)
+	}
+	return issues
+}
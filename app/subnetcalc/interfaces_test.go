@@ -0,0 +1,80 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ifaddr(iface, addr string) InterfaceAddress {
+	return InterfaceAddress{Interface: iface, Address: netip.MustParsePrefix(addr)}
+}
+
+func TestCheckInterfaceAddresses(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []InterfaceAddress
+		want  []InterfaceIssue
+	}{
+		{
+			"clean host",
+			[]InterfaceAddress{
+				ifaddr("lo", "127.0.0.1/8"),
+				ifaddr("eth0", "192.168.1.10/24"),
+				ifaddr("eth0", "fe80::1/64"),
+				ifaddr("eth1", "fe80::2/64"),
+				ifaddr("eth1", "10.0.0.1/31"),
+			},
+			nil,
+		},
+		{
+			"network and broadcast addresses",
+			[]InterfaceAddress{
+				ifaddr("eth0", "192.168.1.0/24"),
+				ifaddr("eth1", "10.0.0.255/24"),
+				ifaddr("eth2", "10.0.9.0/31"),
+			},
+			[]InterfaceIssue{
+				{"eth0", netip.MustParsePrefix("192.168.1.0/24"), "address is the network address of 192.168.1.0/24"},
+				{"eth1", netip.MustParsePrefix("10.0.0.255/24"), "address is the broadcast address of 10.0.0.0/24"},
+			},
+		},
+		{
+			"overlap across interfaces",
+			[]InterfaceAddress{
+				ifaddr("eth0", "10.1.0.5/16"),
+				ifaddr("wg0", "10.1.2.1/24"),
+			},
+			[]InterfaceIssue{
+				{"eth0", netip.MustParsePrefix("10.1.0.5/16"), "subnet 10.1.0.0/16 overlaps 10.1.2.1/24 on wg0"},
+				{"wg0", netip.MustParsePrefix("10.1.2.1/24"), "subnet 10.1.2.0/24 overlaps 10.1.0.5/16 on eth0"},
+			},
+		},
+		{
+			"secondary address on same interface",
+			[]InterfaceAddress{
+				ifaddr("eth0", "10.1.0.5/24"),
+				ifaddr("eth0", "10.1.0.6/24"),
+			},
+			nil,
+		},
+		{
+			"ipv6 overlap",
+			[]InterfaceAddress{
+				ifaddr("eth0", "2001:db8::1/64"),
+				ifaddr("eth1", "2001:db8::2/64"),
+			},
+			[]InterfaceIssue{
+				{"eth0", netip.MustParsePrefix("2001:db8::1/64"), "subnet 2001:db8::/64 overlaps 2001:db8::2/64 on eth1"},
+				{"eth1", netip.MustParsePrefix("2001:db8::2/64"), "subnet 2001:db8::/64 overlaps 2001:db8::1/64 on eth0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckInterfaceAddresses(tt.input))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// IPv6Info describes an IPv6 address or prefix in the notations commonly
+// needed when configuring or troubleshooting IPv6.
+//
+// Prefix is the masked network; for a bare address it is the /128 host
+// prefix. Binary holds the network bits of Prefix, grouped by hextet.
+type IPv6Info struct {
+	Address    netip.Addr
+	Zone       string
+	Prefix     netip.Prefix
+	Compressed string
+	Expanded   string
+	Reverse    string
+	Binary     string
+	TotalIP    *big.Int
+}
+
+// ParseIPv6 parses an IPv6 address or prefix, optionally scoped to a zone as
+// in "fe80::1%eth0" or "fe80::1%eth0/64", and describes it.
+//
+// Compressed is the RFC 5952 canonical text form and Expanded spells out all
+// 32 nibbles. Reverse is the ip6.arpa name of the address.
+func ParseIPv6(s string) (IPv6Info, error) {
+	addrPart, bitsPart, hasBits := strings.Cut(s, "/")
+	addr, err := netip.ParseAddr(addrPart)
+	if err != nil {
+		return IPv6Info{}, err
+	}
+	if !addr.Is6() {
+		return IPv6Info{}, fmt.Errorf("%s is not an IPv6 address", addrPart)
+	}
+
+	bits := addr.BitLen()
+	if hasBits {
+		bits, err = strconv.Atoi(bitsPart)
+		if err != nil || bits < 0 || bits > addr.BitLen() || bitsPart != strconv.Itoa(bits) {
+			return IPv6Info{}, fmt.Errorf("invalid prefix length %q", bitsPart)
+		}
+	}
+
+	info := describeIPv6(netip.PrefixFrom(addr.WithZone(""), bits))
+	info.Zone = addr.Zone()
+	return info, nil
+}
+
+// describeIPv6 returns the notations of a valid IPv6 prefix, keeping the
+// address as written with any host bits.
+func describeIPv6(prefix netip.Prefix) IPv6Info {
+	addr := prefix.Addr()
+	return IPv6Info{
+		Address:    addr,
+		Prefix:     prefix.Masked(),
+		Compressed: addr.String(),
+		Expanded:   addr.StringExpanded(),
+		Reverse:    ReverseName(addr),
+		Binary:     binaryPrefix(prefix.Masked()),
+		TotalIP:    new(big.Int).Lsh(big.NewInt(1), uint(addr.BitLen()-prefix.Bits())),
+	}
+}
+
+// binaryPrefix writes the network bits of prefix in binary, separating
+// hextets with ':' for IPv6 and octets with '.' for IPv4.
+func binaryPrefix(prefix netip.Prefix) string {
+	if !prefix.IsValid() {
+		return ""
+	}
+	groupBits, sep := 16, ":"
+	if prefix.Addr().Is4() {
+		groupBits, sep = 8, "."
+	}
+
+	b := prefix.Addr().AsSlice()
+	var sb strings.Builder
+	for i := 0; i < prefix.Bits(); i++ {
+		if i > 0 && i%groupBits == 0 {
+			sb.WriteString(sep)
+		}
+		if b[i/8]&(0x80>>(i%8)) != 0 {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleParseIPv6() {
+	info, _ := ParseIPv6("2001:DB8:0:0:0:0:2:1")
+	fmt.Println(info.Compressed)
+	fmt.Println(info.Expanded)
+	// Output:
+	// 2001:db8::2:1
+	// 2001:0db8:0000:0000:0000:0000:0002:0001
+}
+
+func TestParseIPv6_Prefix(t *testing.T) {
+	info, err := ParseIPv6("2001:db8:abcd:12::1/36")
+	require.NoError(t, err)
+
+	assert.Equal(t, netip.MustParseAddr("2001:db8:abcd:12::1"), info.Address)
+	assert.Empty(t, info.Zone)
+	assert.Equal(t, netip.MustParsePrefix("2001:db8:a000::/36"), info.Prefix)
+	assert.Equal(t, "2001:db8:abcd:12::1", info.Compressed)
+	assert.Equal(t, "2001:0db8:abcd:0012:0000:0000:0000:0001", info.Expanded)
+	assert.Equal(t, "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.2.1.0.0.d.c.b.a.8.b.d.0.1.0.0.2.ip6.arpa.", info.Reverse)
+	assert.Equal(t, "0010000000000001:0000110110111000:1010", info.Binary)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 92), info.TotalIP)
+}
+
+func TestParseIPv6_Zone(t *testing.T) {
+	info, err := ParseIPv6("fe80::1%eth0/64")
+	require.NoError(t, err)
+	assert.Equal(t, "eth0", info.Zone)
+	assert.Equal(t, netip.MustParseAddr("fe80::1"), info.Address)
+	assert.Equal(t, netip.MustParsePrefix("fe80::/64"), info.Prefix)
+
+	info, err = ParseIPv6("fe80::1%en0")
+	require.NoError(t, err)
+	assert.Equal(t, "en0", info.Zone)
+	assert.Equal(t, netip.MustParsePrefix("fe80::1/128"), info.Prefix)
+	assert.Equal(t, big.NewInt(1), info.TotalIP)
+}
+
+func TestParseIPv6_Errors(t *testing.T) {
+	for _, s := range []string{"192.0.2.1", "2001:db8::/129", "2001:db8::/064", "2001:db8::/x", "2001:db8:::1"} {
+		_, err := ParseIPv6(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBinaryPrefix_IPv4(t *testing.T) {
+	assert.Equal(t, "11000000.10101000.0001", binaryPrefix(netip.MustParsePrefix("192.168.16.0/20")))
+	assert.Empty(t, binaryPrefix(netip.MustParsePrefix("0.0.0.0/0")))
+}
@@ -0,0 +1,128 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+)
+
+// maxServiceHostBits is the largest service CIDR kube-apiserver accepts:
+// a /12 for IPv4 and a /108 for IPv6.
+const maxServiceHostBits = 20
+
+// KubernetesNetworks are the address ranges and sizing knobs of a cluster.
+//
+// NodeCIDR is the subnet nodes take their addresses from, PodCIDR the
+// cluster CIDR carved into per-node ranges of NodeCIDRMaskSize, and
+// ServiceCIDR the range for ClusterIP services.
+type KubernetesNetworks struct {
+	NodeCIDR         netip.Prefix `json:"node_cidr" yaml:"node_cidr"`
+	PodCIDR          netip.Prefix `json:"pod_cidr" yaml:"pod_cidr"`
+	ServiceCIDR      netip.Prefix `json:"service_cidr" yaml:"service_cidr"`
+	MaxPodsPerNode   uint64       `json:"max_pods_per_node" yaml:"max_pods_per_node"`
+	NodeCIDRMaskSize int          `json:"node_cidr_mask_size" yaml:"node_cidr_mask_size"`
+}
+
+// KubernetesPlan is the capacity of a cluster computed by PlanKubernetes.
+//
+// MaxNodes is the smaller of NodesByNodeCIDR and NodesByPodCIDR. PodsPerNode
+// is MaxPodsPerNode capped by the addresses of one per-node pod range.
+type KubernetesPlan struct {
+	NodesByNodeCIDR     uint64   `json:"nodes_by_node_cidr" yaml:"nodes_by_node_cidr"`
+	NodesByPodCIDR      uint64   `json:"nodes_by_pod_cidr" yaml:"nodes_by_pod_cidr"`
+	MaxNodes            uint64   `json:"max_nodes" yaml:"max_nodes"`
+	PodAddressesPerNode uint64   `json:"pod_addresses_per_node" yaml:"pod_addresses_per_node"`
+	PodsPerNode         uint64   `json:"pods_per_node" yaml:"pods_per_node"`
+	MaxPods             uint64   `json:"max_pods" yaml:"max_pods"`
+	MaxServices         uint64   `json:"max_services" yaml:"max_services"`
+	Overlaps            []string `json:"overlaps,omitempty" yaml:"overlaps,omitempty"`
+	Warnings            []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// PlanKubernetes computes how many nodes, pods and services a cluster with the
+// given networks can hold. Overlapping ranges are reported in Overlaps and
+// capacity problems in Warnings; both are returned alongside a valid plan.
+func PlanKubernetes(networks KubernetesNetworks) (KubernetesPlan, error) {
+	node, pod, svc := networks.NodeCIDR, networks.PodCIDR, networks.ServiceCIDR
+	if !node.IsValid() || !pod.IsValid() || !svc.IsValid() {
+		return KubernetesPlan{}, errors.New("invalid prefix")
+	}
+	node, pod, svc = node.Masked(), pod.Masked(), svc.Masked()
+
+	maskSize := networks.NodeCIDRMaskSize
+	if maskSize < pod.Bits() || maskSize > pod.Addr().BitLen() {
+		return KubernetesPlan{}, fmt.Errorf("node CIDR mask size /%d does not fit in pod CIDR %s", maskSize, pod)
+	}
+	if networks.MaxPodsPerNode == 0 {
+		return KubernetesPlan{}, errors.New("max pods per node must be positive")
+	}
+	if svc.Addr().BitLen()-svc.Bits() > maxServiceHostBits {
+		return KubernetesPlan{}, fmt.Errorf("service CIDR %s is larger than /%d", svc, svc.Addr().BitLen()-maxServiceHostBits)
+	}
+
+	var plan KubernetesPlan
+	plan.NodesByNodeCIDR = usableAddresses(node)
+	plan.NodesByPodCIDR = pow2(maskSize - pod.Bits())
+	plan.MaxNodes = min(plan.NodesByNodeCIDR, plan.NodesByPodCIDR)
+	plan.PodAddressesPerNode = usableAddresses(netip.PrefixFrom(pod.Addr(), maskSize))
+	plan.PodsPerNode = min(networks.MaxPodsPerNode, plan.PodAddressesPerNode)
+	plan.MaxPods = saturatingMul(plan.MaxNodes, plan.PodsPerNode)
+	plan.MaxServices = usableAddresses(svc)
+
+	ranges := []struct {
+		name   string
+		prefix netip.Prefix
+	}{{"node CIDR", node}, {"pod CIDR", pod}, {"service CIDR", svc}}
+	for i := range ranges {
+		for _, other := range ranges[i+1:] {
+			if ranges[i].prefix.Overlaps(other.prefix) {
+				plan.Overlaps = append(plan.Overlaps, fmt.Sprintf("%s %s overlaps %s %s", ranges[i].name, ranges[i].prefix, other.name, other.prefix))
+			}
+		}
+	}
+
+	if plan.NodesByPodCIDR < plan.NodesByNodeCIDR {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"pod CIDR %s only has room for %d /%d node ranges; the node CIDR could hold %d nodes",
+			pod, plan.NodesByPodCIDR, maskSize, plan.NodesByNodeCIDR))
+	}
+	if networks.MaxPodsPerNode > plan.PodAddressesPerNode {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"max pods per node %d exceeds the %d usable addresses of a /%d node range",
+			networks.MaxPodsPerNode, plan.PodAddressesPerNode, maskSize))
+	}
+	return plan, nil
+}
+
+// usableAddresses returns the number of assignable addresses in prefix:
+// IPv4 loses its network and broadcast addresses on prefixes shorter than
+// /31, IPv6 loses the subnet-router anycast address on prefixes shorter
+// than /127. Counts that do not fit in a uint64 saturate.
+func usableAddresses(prefix netip.Prefix) uint64 {
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	total := pow2(hostBits)
+	switch {
+	case hostBits <= 1 || hostBits >= 64:
+		return total
+	case prefix.Addr().Is4():
+		return total - 2
+	default:
+		return total - 1
+	}
+}
+
+// pow2 returns 2^n, saturating at math.MaxUint64.
+func pow2(n int) uint64 {
+	if n >= 64 {
+		return math.MaxUint64
+	}
+	return 1 << n
+}
+
+func saturatingMul(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
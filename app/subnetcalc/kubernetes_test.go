@@ -0,0 +1,157 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExamplePlanKubernetes() {
+	plan, _ := PlanKubernetes(KubernetesNetworks{
+		NodeCIDR:         netip.MustParsePrefix("10.0.0.0/22"),
+		PodCIDR:          netip.MustParsePrefix("10.244.0.0/16"),
+		ServiceCIDR:      netip.MustParsePrefix("10.96.0.0/12"),
+		MaxPodsPerNode:   110,
+		NodeCIDRMaskSize: 24,
+	})
+	fmt.Println(plan.MaxNodes, plan.PodsPerNode, plan.MaxPods, plan.MaxServices)
+	fmt.Println(plan.Warnings)
+	// Output:
+	// 256 110 28160 1048574
+	// [pod CIDR 10.244.0.0/16 only has room for 256 /24 node ranges; the node CIDR could hold 1022 nodes]
+}
+
+func TestPlanKubernetes(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks KubernetesNetworks
+		want     KubernetesPlan
+	}{
+		{
+			"limited by node subnet",
+			KubernetesNetworks{
+				NodeCIDR:         netip.MustParsePrefix("10.0.0.0/24"),
+				PodCIDR:          netip.MustParsePrefix("10.244.0.0/14"),
+				ServiceCIDR:      netip.MustParsePrefix("10.96.0.0/16"),
+				MaxPodsPerNode:   110,
+				NodeCIDRMaskSize: 24,
+			},
+			KubernetesPlan{
+				NodesByNodeCIDR:     254,
+				NodesByPodCIDR:      1024,
+				MaxNodes:            254,
+				PodAddressesPerNode: 254,
+				PodsPerNode:         110,
+				MaxPods:             27940,
+				MaxServices:         65534,
+			},
+		},
+		{
+			"small node ranges cap pods per node",
+			KubernetesNetworks{
+				NodeCIDR:         netip.MustParsePrefix("172.16.0.0/26"),
+				PodCIDR:          netip.MustParsePrefix("10.0.0.0/16"),
+				ServiceCIDR:      netip.MustParsePrefix("10.1.0.0/24"),
+				MaxPodsPerNode:   110,
+				NodeCIDRMaskSize: 26,
+			},
+			KubernetesPlan{
+				NodesByNodeCIDR:     62,
+				NodesByPodCIDR:      1024,
+				MaxNodes:            62,
+				PodAddressesPerNode: 62,
+				PodsPerNode:         62,
+				MaxPods:             3844,
+				MaxServices:         254,
+				Warnings:            []string{"max pods per node 110 exceeds the 62 usable addresses of a /26 node range"},
+			},
+		},
+		{
+			"ipv6 pods",
+			KubernetesNetworks{
+				NodeCIDR:         netip.MustParsePrefix("2001:db8::/64"),
+				PodCIDR:          netip.MustParsePrefix("fd00:10::/56"),
+				ServiceCIDR:      netip.MustParsePrefix("fd00:20::/112"),
+				MaxPodsPerNode:   250,
+				NodeCIDRMaskSize: 64,
+			},
+			KubernetesPlan{
+				NodesByNodeCIDR:     math.MaxUint64,
+				NodesByPodCIDR:      256,
+				MaxNodes:            256,
+				PodAddressesPerNode: math.MaxUint64,
+				PodsPerNode:         250,
+				MaxPods:             64000,
+				MaxServices:         65535,
+				Warnings:            []string{"pod CIDR fd00:10::/56 only has room for 256 /64 node ranges; the node CIDR could hold 18446744073709551615 nodes"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlanKubernetes(tt.networks)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPlanKubernetes_Overlaps(t *testing.T) {
+	plan, err := PlanKubernetes(KubernetesNetworks{
+		NodeCIDR:         netip.MustParsePrefix("10.0.0.0/16"),
+		PodCIDR:          netip.MustParsePrefix("10.0.0.0/8"),
+		ServiceCIDR:      netip.MustParsePrefix("10.96.0.0/12"),
+		MaxPodsPerNode:   110,
+		NodeCIDRMaskSize: 24,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"node CIDR 10.0.0.0/16 overlaps pod CIDR 10.0.0.0/8",
+		"pod CIDR 10.0.0.0/8 overlaps service CIDR 10.96.0.0/12",
+	}, plan.Overlaps)
+}
+
+func TestPlanKubernetes_Errors(t *testing.T) {
+	valid := KubernetesNetworks{
+		NodeCIDR:         netip.MustParsePrefix("10.0.0.0/24"),
+		PodCIDR:          netip.MustParsePrefix("10.244.0.0/16"),
+		ServiceCIDR:      netip.MustParsePrefix("10.96.0.0/12"),
+		MaxPodsPerNode:   110,
+		NodeCIDRMaskSize: 24,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*KubernetesNetworks)
+		wantErr string
+	}{
+		{"missing prefix", func(n *KubernetesNetworks) { n.PodCIDR = netip.Prefix{} }, "invalid prefix"},
+		{"mask shorter than pod CIDR", func(n *KubernetesNetworks) { n.NodeCIDRMaskSize = 12 }, "node CIDR mask size /12 does not fit in pod CIDR 10.244.0.0/16"},
+		{"mask too long", func(n *KubernetesNetworks) { n.NodeCIDRMaskSize = 33 }, "node CIDR mask size /33 does not fit in pod CIDR 10.244.0.0/16"},
+		{"no pods", func(n *KubernetesNetworks) { n.MaxPodsPerNode = 0 }, "max pods per node must be positive"},
+		{
+			"service CIDR too large",
+			func(n *KubernetesNetworks) { n.ServiceCIDR = netip.MustParsePrefix("10.64.0.0/11") },
+			"service CIDR 10.64.0.0/11 is larger than /12",
+		},
+		{
+			"ipv6 service CIDR too large",
+			func(n *KubernetesNetworks) { n.ServiceCIDR = netip.MustParsePrefix("fd00::/64") },
+			"service CIDR fd00::/64 is larger than /108",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks := valid
+			tt.modify(&networks)
+			_, err := PlanKubernetes(networks)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
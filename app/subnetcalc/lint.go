@@ -0,0 +1,188 @@
+package subnetcalc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Severity ranks lint findings.
+type Severity string
+
+// Lint severities, from most to least severe.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Lint rules.
+const (
+	RuleInvalid      = "invalid"
+	RuleHostBits     = "host-bits"
+	RuleDuplicate    = "duplicate"
+	RuleOverlap      = "overlap"
+	RuleNonCanonical = "non-canonical"
+	RuleReserved     = "reserved"
+)
+
+// LintFinding is a problem found on one line of a prefix list.
+type LintFinding struct {
+	Line     int      `json:"line" yaml:"line"`
+	Severity Severity `json:"severity" yaml:"severity"`
+	Rule     string   `json:"rule" yaml:"rule"`
+	Message  string   `json:"message" yaml:"message"`
+}
+
+// LintOptions controls LintPrefixList.
+//
+// Public marks the list as holding public address space, such as an
+// allowlist of customer networks or announced routes; special-purpose ranges
+// like RFC 1918 or documentation prefixes are then reported.
+type LintOptions struct {
+	Public bool
+}
+
+// specialPurpose lists the special-purpose ranges reported in public lists
+// (RFC 6890 and the IANA special-purpose registries).
+var specialPurpose = []struct {
+	prefix netip.Prefix
+	name   string
+}{
+	{netip.MustParsePrefix("0.0.0.0/8"), "\"this network\" (RFC 791)"},
+	{netip.MustParsePrefix("10.0.0.0/8"), "private (RFC 1918)"},
+	{netip.MustParsePrefix("100.64.0.0/10"), "shared address space (RFC 6598)"},
+	{netip.MustParsePrefix("127.0.0.0/8"), "loopback"},
+	{netip.MustParsePrefix("169.254.0.0/16"), "link-local"},
+	{netip.MustParsePrefix("172.16.0.0/12"), "private (RFC 1918)"},
+	{netip.MustParsePrefix("192.0.0.0/24"), "IETF protocol assignments"},
+	{netip.MustParsePrefix("192.0.2.0/24"), "documentation (TEST-NET-1)"},
+	{netip.MustParsePrefix("192.168.0.0/16"), "private (RFC 1918)"},
+	{netip.MustParsePrefix("198.18.0.0/15"), "benchmarking (RFC 2544)"},
+	{netip.MustParsePrefix("198.51.100.0/24"), "documentation (TEST-NET-2)"},
+	{netip.MustParsePrefix("203.0.113.0/24"), "documentation (TEST-NET-3)"},
+	{netip.MustParsePrefix("224.0.0.0/4"), "multicast"},
+	{netip.MustParsePrefix("240.0.0.0/4"), "reserved"},
+	{netip.MustParsePrefix("::/128"), "unspecified"},
+	{netip.MustParsePrefix("::1/128"), "loopback"},
+	{netip.MustParsePrefix("::ffff:0:0/96"), "IPv4-mapped"},
+	{netip.MustParsePrefix("64:ff9b:1::/48"), "local-use NAT64 (RFC 8215)"},
+	{netip.MustParsePrefix("100::/64"), "discard-only (RFC 6666)"},
+	{netip.MustParsePrefix("2001:db8::/32"), "documentation"},
+	{netip.MustParsePrefix("fc00::/7"), "unique local (RFC 4193)"},
+	{netip.MustParsePrefix("fe80::/10"), "link-local"},
+	{netip.MustParsePrefix("ff00::/8"), "multicast"},
+}
+
+// PrefixListLine is an entry of a prefix list: the prefix as written, the
+// label following it and the line it is on.
+type PrefixListLine struct {
+	Line  int
+	Token string
+	Label string
+}
+
+// ReadPrefixList reads the entries of a prefix list: one prefix per line,
+// an optional label after it, blank lines and anything after '#' ignored.
+// Tokens are returned unparsed, so callers can decide which notations they
+// accept.
+func ReadPrefixList(r io.Reader) ([]PrefixListLine, error) {
+	var entries []PrefixListLine
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		entries = append(entries, PrefixListLine{Line: line, Token: fields[0], Label: strings.Join(fields[1:], " ")})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParsePrefixOrAddr parses a CIDR prefix, or a bare address as a host
+// prefix, as accepted in prefix lists and on the command line.
+func ParsePrefixOrAddr(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// LintPrefixList checks a prefix list in the format read by the list
+// commands: one prefix or bare address per line, an optional label after it,
+// blank lines and '#' comments ignored. Findings are sorted by line.
+//
+// Unparseable lines, prefixes with host bits set and duplicates are errors;
+// overlaps, IPv6 prefixes not written in RFC 5952 form and, for public lists,
+// special-purpose ranges are warnings.
+func LintPrefixList(r io.Reader, opts LintOptions) ([]LintFinding, error) {
+	var findings []LintFinding
+	add := func(line int, severity Severity, rule, format string, args ...any) {
+		findings = append(findings, LintFinding{Line: line, Severity: severity, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	lines, err := ReadPrefixList(r)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[netip.Prefix]int)
+	var entries []LabeledPrefix
+	for _, l := range lines {
+		line, token := l.Line, l.Token
+		prefix, err := ParsePrefixOrAddr(token)
+		if err != nil {
+			add(line, SeverityError, RuleInvalid, "%q is not a prefix or address", token)
+			continue
+		}
+		masked := prefix.Masked()
+		if prefix != masked {
+			add(line, SeverityError, RuleHostBits, "%s has host bits set; did you mean %s?", token, masked)
+		}
+		canonical := prefix.String()
+		if !strings.Contains(token, "/") {
+			canonical = prefix.Addr().String()
+		}
+		if prefix.Addr().Is6() && token != canonical {
+			add(line, SeverityWarning, RuleNonCanonical, "%s is not in canonical form; write %s", token, canonical)
+		}
+		if first, ok := seen[masked]; ok {
+			add(line, SeverityError, RuleDuplicate, "%s duplicates line %d", masked, first)
+			continue
+		}
+		seen[masked] = line
+		entries = append(entries, LabeledPrefix{Prefix: masked})
+
+		if opts.Public {
+			for _, sp := range specialPurpose {
+				if sp.prefix.Overlaps(masked) {
+					add(line, SeverityWarning, RuleReserved, "%s overlaps %s %s", masked, sp.name, sp.prefix)
+				}
+			}
+		}
+	}
+
+	for _, c := range FindConflicts(entries) {
+		a, b := seen[c.A.Prefix], seen[c.B.Prefix]
+		if a > b {
+			a, b = b, a
+			c.A, c.B = c.B, c.A
+		}
+		add(b, SeverityWarning, RuleOverlap, "%s overlaps %s on line %d", c.B.Prefix, c.A.Prefix, a)
+	}
+
+	slices.SortStableFunc(findings, func(x, y LintFinding) int { return x.Line - y.Line })
+	return findings, nil
+}
@@ -0,0 +1,80 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPrefixList(t *testing.T) {
+	lines, err := ReadPrefixList(strings.NewReader("# comment\n\n10.0.0.0/24  web  tier # frontend\n  192.0.2.1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []PrefixListLine{
+		{Line: 3, Token: "10.0.0.0/24", Label: "web tier"},
+		{Line: 4, Token: "192.0.2.1"},
+	}, lines)
+}
+
+func TestParsePrefixOrAddr(t *testing.T) {
+	prefix, err := ParsePrefixOrAddr("10.0.0.0/8")
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), prefix)
+
+	prefix, err = ParsePrefixOrAddr("2001:db8::1")
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("2001:db8::1/128"), prefix)
+
+	_, err = ParsePrefixOrAddr("10.0.0")
+	assert.Error(t, err)
+}
+
+func TestLintPrefixList_BareAddresses(t *testing.T) {
+	findings, err := LintPrefixList(strings.NewReader("10.0.0.1\n2001:db8::1\n2001:DB8::2\n10.0.0.1/32\n"), LintOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []LintFinding{
+		{Line: 3, Severity: SeverityWarning, Rule: RuleNonCanonical, Message: "2001:DB8::2 is not in canonical form; write 2001:db8::2"},
+		{Line: 4, Severity: SeverityError, Rule: RuleDuplicate, Message: "10.0.0.1/32 duplicates line 1"},
+	}, findings)
+}
+
+func TestLintPrefixList(t *testing.T) {
+	list := `# office networks
+10.0.0.0/24 hq
+10.0.1.5/24 branch
+10.0.0.0/24 hq again
+
+10.0.0.128/25
+2001:DB8:0::/48
+not-a-prefix
+10.0.1.0/24
+`
+	findings, err := LintPrefixList(strings.NewReader(list), LintOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []LintFinding{
+		{Line: 3, Severity: SeverityError, Rule: RuleHostBits, Message: "10.0.1.5/24 has host bits set; did you mean 10.0.1.0/24?"},
+		{Line: 4, Severity: SeverityError, Rule: RuleDuplicate, Message: "10.0.0.0/24 duplicates line 2"},
+		{Line: 6, Severity: SeverityWarning, Rule: RuleOverlap, Message: "10.0.0.128/25 overlaps 10.0.0.0/24 on line 2"},
+		{Line: 7, Severity: SeverityWarning, Rule: RuleNonCanonical, Message: "2001:DB8:0::/48 is not in canonical form; write 2001:db8::/48"},
+		{Line: 8, Severity: SeverityError, Rule: RuleInvalid, Message: `"not-a-prefix" is not a prefix or address`},
+		{Line: 9, Severity: SeverityError, Rule: RuleDuplicate, Message: "10.0.1.0/24 duplicates line 3"},
+	}, findings)
+}
+
+func TestLintPrefixList_Public(t *testing.T) {
+	list := "8.8.8.0/24\n192.168.0.0/16\n2001:db8:1::/48\n172.0.0.0/8\n"
+
+	findings, err := LintPrefixList(strings.NewReader(list), LintOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+
+	findings, err = LintPrefixList(strings.NewReader(list), LintOptions{Public: true})
+	require.NoError(t, err)
+	assert.Equal(t, []LintFinding{
+		{Line: 2, Severity: SeverityWarning, Rule: RuleReserved, Message: "192.168.0.0/16 overlaps private (RFC 1918) 192.168.0.0/16"},
+		{Line: 3, Severity: SeverityWarning, Rule: RuleReserved, Message: "2001:db8:1::/48 overlaps documentation 2001:db8::/32"},
+		{Line: 4, Severity: SeverityWarning, Rule: RuleReserved, Message: "172.0.0.0/8 overlaps private (RFC 1918) 172.16.0.0/12"},
+	}, findings)
+}
@@ -0,0 +1,165 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// MulticastScope is the reach of a multicast group.
+type MulticastScope string
+
+// Multicast scopes. IPv4 scopes follow RFC 5771 and RFC 2365; IPv6 scopes
+// are the scope field of the address (RFC 4291 and RFC 7346). MulticastMixed
+// marks a range whose groups have different scopes.
+const (
+	MulticastInterfaceLocal    MulticastScope = "interface-local"
+	MulticastLinkLocal         MulticastScope = "link-local"
+	MulticastRealmLocal        MulticastScope = "realm-local"
+	MulticastAdminLocal        MulticastScope = "admin-local"
+	MulticastSiteLocal         MulticastScope = "site-local"
+	MulticastOrganizationLocal MulticastScope = "organization-local"
+	MulticastAdminScoped       MulticastScope = "admin-scoped"
+	MulticastGlobal            MulticastScope = "global"
+	MulticastReserved          MulticastScope = "reserved"
+	MulticastMixed             MulticastScope = "mixed"
+)
+
+var (
+	ipv4LocalControl   = netip.MustParsePrefix("224.0.0.0/24")
+	ipv4SSM            = netip.MustParsePrefix("232.0.0.0/8")
+	ipv4GLOP           = netip.MustParsePrefix("233.0.0.0/8")
+	ipv4AdHocIII       = netip.MustParsePrefix("233.252.0.0/14")
+	ipv4AdminScoped    = netip.MustParsePrefix("239.0.0.0/8")
+	ipv4OrgLocalScope  = netip.MustParsePrefix("239.192.0.0/14")
+	ipv4SiteLocalScope = netip.MustParsePrefix("239.255.0.0/16")
+)
+
+// ipv6Scopes maps the scope field of an IPv6 multicast address.
+var ipv6Scopes = map[byte]MulticastScope{
+	0x1: MulticastInterfaceLocal,
+	0x2: MulticastLinkLocal,
+	0x3: MulticastRealmLocal,
+	0x4: MulticastAdminLocal,
+	0x5: MulticastSiteLocal,
+	0x8: MulticastOrganizationLocal,
+	0xe: MulticastGlobal,
+}
+
+// MulticastInfo describes a multicast group address.
+//
+// MAC is the Ethernet multicast address the group maps to. Only the low 23
+// bits of an IPv4 group (32 bits of an IPv6 group) are carried over, so other
+// groups share the MAC; for IPv4 those 31 groups are listed in SharedMAC.
+// Hosts joined to any of them receive each other's traffic at layer 2.
+//
+// GLOPAS is the autonomous system number encoded in a GLOP (233/8, RFC 3180)
+// group and is only meaningful when GLOP is set. For a range spanning the
+// groups of several ASes it is zero.
+type MulticastInfo struct {
+	Address   netip.Addr
+	Scope     MulticastScope
+	SSM       bool
+	GLOP      bool
+	GLOPAS    uint32
+	MAC       net.HardwareAddr
+	SharedMAC []netip.Addr
+}
+
+// AnalyzeMulticast describes a multicast group in 224.0.0.0/4 or ff00::/8.
+func AnalyzeMulticast(addr netip.Addr) (MulticastInfo, error) {
+	addr = addr.WithZone("")
+	if !addr.IsMulticast() {
+		return MulticastInfo{}, fmt.Errorf("%s is not a multicast address", addr)
+	}
+	if addr.Is4() {
+		return analyzeIPv4Multicast(addr), nil
+	}
+	return analyzeIPv6Multicast(addr), nil
+}
+
+// AnalyzeMulticastPrefix describes a multicast range by its network
+// address. Scope, SSM and GLOP are only set when they hold for every group
+// of the range; a range mixing scopes has Scope MulticastMixed. MAC and
+// SharedMAC are only set for a single group.
+func AnalyzeMulticastPrefix(prefix netip.Prefix) (MulticastInfo, error) {
+	prefix = prefix.Masked()
+	info, err := AnalyzeMulticast(prefix.Addr())
+	if err != nil || prefix.IsSingleIP() {
+		return info, err
+	}
+	info.MAC, info.SharedMAC = nil, nil
+
+	// The classification blocks are prefixes themselves, so a range lies in
+	// one when its first and last groups do. IPv6 ranges shorter than /16
+	// also vary the flags and scope fields between those two groups.
+	last, err := AnalyzeMulticast(lastAddr(prefix))
+	if err != nil || last.Scope != info.Scope || (prefix.Addr().Is6() && prefix.Bits() < 16) {
+		info.Scope = MulticastMixed
+	}
+	if err != nil || last.SSM != info.SSM || (prefix.Addr().Is6() && prefix.Bits() < 16) {
+		info.SSM = false
+	}
+	if err != nil || last.GLOP != info.GLOP {
+		info.GLOP = false
+	}
+	if !info.GLOP || last.GLOPAS != info.GLOPAS {
+		info.GLOPAS = 0
+	}
+	return info, nil
+}
+
+func analyzeIPv4Multicast(addr netip.Addr) MulticastInfo {
+	b := addr.As4()
+	info := MulticastInfo{
+		Address: addr,
+		Scope:   MulticastGlobal,
+		MAC:     net.HardwareAddr{0x01, 0x00, 0x5e, b[1] & 0x7f, b[2], b[3]},
+	}
+
+	switch {
+	case ipv4LocalControl.Contains(addr):
+		info.Scope = MulticastLinkLocal
+	case ipv4SSM.Contains(addr):
+		info.SSM = true
+	case ipv4GLOP.Contains(addr) && !ipv4AdHocIII.Contains(addr):
+		info.GLOP = true
+		info.GLOPAS = uint32(b[1])<<8 | uint32(b[2])
+	case ipv4SiteLocalScope.Contains(addr):
+		info.Scope = MulticastSiteLocal
+	case ipv4OrgLocalScope.Contains(addr):
+		info.Scope = MulticastOrganizationLocal
+	case ipv4AdminScoped.Contains(addr):
+		info.Scope = MulticastAdminScoped
+	}
+
+	// The 5 bits dropped by the mapping are the low 4 bits of the first
+	// octet and the high bit of the second.
+	for first := byte(224); first <= 239; first++ {
+		for _, high := range []byte{0x00, 0x80} {
+			shared := netip.AddrFrom4([4]byte{first, b[1]&0x7f | high, b[2], b[3]})
+			if shared != addr {
+				info.SharedMAC = append(info.SharedMAC, shared)
+			}
+		}
+	}
+	return info
+}
+
+func analyzeIPv6Multicast(addr netip.Addr) MulticastInfo {
+	b := addr.As16()
+	flags, scope := b[1]>>4, b[1]&0x0f
+	info := MulticastInfo{
+		Address: addr,
+		Scope:   MulticastReserved,
+		MAC:     net.HardwareAddr{0x33, 0x33, b[12], b[13], b[14], b[15]},
+	}
+	if s, ok := ipv6Scopes[scope]; ok {
+		info.Scope = s
+	}
+
+	// ff3x::/96 is source-specific multicast (RFC 4607): the P and T flags
+	// set and a zero prefix length and network prefix.
+	info.SSM = flags == 0x3 && [10]byte(b[2:12]) == [10]byte{}
+	return info
+}
@@ -0,0 +1,80 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeMulticast_IPv4Scopes(t *testing.T) {
+	tests := []struct {
+		addr  string
+		scope MulticastScope
+		ssm   bool
+	}{
+		{"224.0.0.251", MulticastLinkLocal, false},
+		{"224.0.1.1", MulticastGlobal, false},
+		{"232.1.2.3", MulticastGlobal, true},
+		{"239.255.255.250", MulticastSiteLocal, false},
+		{"239.192.0.1", MulticastOrganizationLocal, false},
+		{"239.1.1.1", MulticastAdminScoped, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			info, err := AnalyzeMulticast(netip.MustParseAddr(tt.addr))
+			require.NoError(t, err)
+			assert.Equal(t, tt.scope, info.Scope)
+			assert.Equal(t, tt.ssm, info.SSM)
+			assert.False(t, info.GLOP)
+		})
+	}
+}
+
+func TestAnalyzeMulticast_GLOP(t *testing.T) {
+	info, err := AnalyzeMulticast(netip.MustParseAddr("233.252.0.1"))
+	require.NoError(t, err)
+	assert.False(t, info.GLOP)
+
+	// RFC 3180: AS 5662 is 0x161e, giving 233.22.30.0/24.
+	info, err = AnalyzeMulticast(netip.MustParseAddr("233.22.30.7"))
+	require.NoError(t, err)
+	assert.True(t, info.GLOP)
+	assert.Equal(t, uint32(5662), info.GLOPAS)
+}
+
+func TestAnalyzeMulticast_IPv4MAC(t *testing.T) {
+	info, err := AnalyzeMulticast(netip.MustParseAddr("239.129.1.1"))
+	require.NoError(t, err)
+	assert.Equal(t, "01:00:5e:01:01:01", info.MAC.String())
+
+	require.Len(t, info.SharedMAC, 31)
+	assert.Equal(t, netip.MustParseAddr("224.1.1.1"), info.SharedMAC[0])
+	assert.Equal(t, netip.MustParseAddr("224.129.1.1"), info.SharedMAC[1])
+	assert.Contains(t, info.SharedMAC, netip.MustParseAddr("239.1.1.1"))
+	assert.NotContains(t, info.SharedMAC, info.Address)
+}
+
+func TestAnalyzeMulticast_IPv6(t *testing.T) {
+	info, err := AnalyzeMulticast(netip.MustParseAddr("ff02::1:ff12:3456"))
+	require.NoError(t, err)
+	assert.Equal(t, MulticastLinkLocal, info.Scope)
+	assert.Equal(t, "33:33:ff:12:34:56", info.MAC.String())
+	assert.False(t, info.SSM)
+	assert.Empty(t, info.SharedMAC)
+
+	info, err = AnalyzeMulticast(netip.MustParseAddr("ff3e::8000:1"))
+	require.NoError(t, err)
+	assert.Equal(t, MulticastGlobal, info.Scope)
+	assert.True(t, info.SSM)
+
+	info, err = AnalyzeMulticast(netip.MustParseAddr("ff07::1"))
+	require.NoError(t, err)
+	assert.Equal(t, MulticastReserved, info.Scope)
+}
+
+func TestAnalyzeMulticast_NotMulticast(t *testing.T) {
+	_, err := AnalyzeMulticast(netip.MustParseAddr("10.0.0.1"))
+	assert.EqualError(t, err, "10.0.0.1 is not a multicast address")
+}
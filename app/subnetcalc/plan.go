@@ -0,0 +1,200 @@
+package subnetcalc
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/netip"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Plan is a hierarchical address plan: sites contain VPCs, which contain
+// subnets. It is stored as YAML:
+//
+//	sites:
+//	  - name: fra1
+//	    purpose: Frankfurt data center
+//	    prefix: 10.0.0.0/12
+//	    vpcs:
+//	      - name: prod
+//	        prefix: 10.0.0.0/16
+//	        subnets:
+//	          - name: web
+//	            purpose: public web tier
+//	            prefix: 10.0.1.0/24
+type Plan struct {
+	Sites []PlanSite `yaml:"sites"`
+}
+
+// PlanSite is a top-level entry of a Plan.
+type PlanSite struct {
+	Name    string       `yaml:"name"`
+	Purpose string       `yaml:"purpose,omitempty"`
+	Prefix  netip.Prefix `yaml:"prefix"`
+	VPCs    []PlanVPC    `yaml:"vpcs,omitempty"`
+}
+
+// PlanVPC is a VPC or VNet inside a site.
+type PlanVPC struct {
+	Name    string       `yaml:"name"`
+	Purpose string       `yaml:"purpose,omitempty"`
+	Prefix  netip.Prefix `yaml:"prefix"`
+	Subnets []PlanSubnet `yaml:"subnets,omitempty"`
+}
+
+// PlanSubnet is a subnet inside a VPC.
+type PlanSubnet struct {
+	Name    string       `yaml:"name"`
+	Purpose string       `yaml:"purpose,omitempty"`
+	Prefix  netip.Prefix `yaml:"prefix"`
+}
+
+// PlanFinding is a problem found by ValidatePlan. Path names the entry, as
+// in "fra1/prod/web".
+type PlanFinding struct {
+	Path    string `json:"path" yaml:"path"`
+	Problem string `json:"problem" yaml:"problem"`
+}
+
+// PlanUtilization is the share of an entry's addresses that its children
+// allocate. Depth is 0 for sites and 1 for VPCs; subnets are leaves and have
+// no utilization.
+type PlanUtilization struct {
+	Path        string       `json:"path" yaml:"path"`
+	Depth       int          `json:"depth" yaml:"depth"`
+	Prefix      netip.Prefix `json:"prefix" yaml:"prefix"`
+	Utilization float64      `json:"utilization" yaml:"utilization"`
+}
+
+// PlanReport is the result of ValidatePlan.
+type PlanReport struct {
+	Findings    []PlanFinding     `json:"findings" yaml:"findings"`
+	Utilization []PlanUtilization `json:"utilization" yaml:"utilization"`
+}
+
+// planSubnetDepth is the depth of subnets, the leaves of a plan.
+const planSubnetDepth = 2
+
+// planNode is a level-independent view of a plan entry.
+type planNode struct {
+	path     string
+	prefix   netip.Prefix
+	children []planNode
+}
+
+// ParsePlan decodes a YAML plan. Unknown keys are rejected so typos do not
+// silently drop entries.
+func ParsePlan(data []byte) (Plan, error) {
+	var plan Plan
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&plan); err != nil {
+		return Plan{}, fmt.Errorf("invalid plan: %w", err)
+	}
+	return plan, nil
+}
+
+// ValidatePlan checks that every entry has a name and a prefix without host
+// bits set, that every child lies inside its parent, and that siblings do
+// not overlap. It also reports how much of each entry its children use.
+func ValidatePlan(plan Plan) PlanReport {
+	var report PlanReport
+	roots := plan.nodes()
+	report.validateLevel(roots)
+	for _, root := range roots {
+		report.walk(root, 0)
+	}
+	return report
+}
+
+// Prefixes returns every entry of the plan labeled with its path, parents
+// before their children.
+func (p Plan) Prefixes() []LabeledPrefix {
+	var prefixes []LabeledPrefix
+	var add func(nodes []planNode)
+	add = func(nodes []planNode) {
+		for _, n := range nodes {
+			prefixes = append(prefixes, LabeledPrefix{Label: n.path, Prefix: n.prefix})
+			add(n.children)
+		}
+	}
+	add(p.nodes())
+	return prefixes
+}
+
+func (p Plan) nodes() []planNode {
+	sites := make([]planNode, 0, len(p.Sites))
+	for _, site := range p.Sites {
+		siteNode := planNode{path: site.Name, prefix: site.Prefix}
+		for _, vpc := range site.VPCs {
+			vpcNode := planNode{path: site.Name + "/" + vpc.Name, prefix: vpc.Prefix}
+			for _, subnet := range vpc.Subnets {
+				vpcNode.children = append(vpcNode.children, planNode{path: vpcNode.path + "/" + subnet.Name, prefix: subnet.Prefix})
+			}
+			siteNode.children = append(siteNode.children, vpcNode)
+		}
+		sites = append(sites, siteNode)
+	}
+	return sites
+}
+
+func (r *PlanReport) walk(n planNode, depth int) {
+	if depth < planSubnetDepth {
+		usage := PlanUtilization{Path: n.path, Depth: depth, Prefix: n.prefix}
+		if n.prefix.IsValid() {
+			var children []netip.Prefix
+			for _, c := range n.children {
+				children = append(children, c.prefix)
+			}
+			used := NewPrefixSet(children...).Intersect(NewPrefixSet(n.prefix))
+			usage.Utilization = addressCount(used) / addressCount(NewPrefixSet(n.prefix))
+		}
+		r.Utilization = append(r.Utilization, usage)
+	}
+
+	r.validateLevel(n.children)
+	for _, c := range n.children {
+		if n.prefix.IsValid() && c.prefix.IsValid() && !NewPrefixSet(n.prefix).ContainsPrefix(c.prefix) {
+			r.addFinding(c.path, fmt.Sprintf("%s is not inside parent %s", c.prefix, n.prefix))
+		}
+		r.walk(c, depth+1)
+	}
+}
+
+// validateLevel checks the entries that share a parent.
+func (r *PlanReport) validateLevel(siblings []planNode) {
+	labeled := make([]LabeledPrefix, 0, len(siblings))
+	for i, n := range siblings {
+		path := n.path
+		if path == "" || path[len(path)-1] == '/' {
+			path = fmt.Sprintf("%s[%d]", path, i)
+			r.addFinding(path, "missing name")
+		}
+		switch {
+		case !n.prefix.IsValid():
+			r.addFinding(path, "missing prefix")
+			continue
+		case n.prefix != n.prefix.Masked():
+			r.addFinding(path, fmt.Sprintf("%s is not aligned; did you mean %s?", n.prefix, n.prefix.Masked()))
+		}
+		labeled = append(labeled, LabeledPrefix{Label: path, Prefix: n.prefix})
+	}
+	for _, c := range FindConflicts(labeled) {
+		r.addFinding(c.B.Label, fmt.Sprintf("%s overlaps sibling %s (%s)", c.B.Prefix, c.A.Label, c.A.Prefix))
+	}
+}
+
+func (r *PlanReport) addFinding(path, problem string) {
+	r.Findings = append(r.Findings, PlanFinding{Path: path, Problem: problem})
+}
+
+// addressCount returns the number of addresses in s as a float64, which is
+// exact for IPv4 and close enough for ratios of IPv6 sizes.
+func addressCount(s PrefixSet) float64 {
+	var total float64
+	for _, p := range s.Prefixes() {
+		total += math.Ldexp(1, p.Addr().BitLen()-p.Bits())
+	}
+	return total
+}
@@ -0,0 +1,101 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validPlanYAML = `
+sites:
+  - name: fra1
+    purpose: Frankfurt data center
+    prefix: 10.0.0.0/15
+    vpcs:
+      - name: prod
+        prefix: 10.0.0.0/16
+        subnets:
+          - name: web
+            purpose: public web tier
+            prefix: 10.0.0.0/17
+          - name: db
+            prefix: 10.0.128.0/18
+  - name: ams1
+    prefix: 10.2.0.0/16
+`
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan([]byte(validPlanYAML))
+	require.NoError(t, err)
+
+	require.Len(t, plan.Sites, 2)
+	assert.Equal(t, "Frankfurt data center", plan.Sites[0].Purpose)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/15"), plan.Sites[0].Prefix)
+	assert.Equal(t, PlanSubnet{Name: "web", Purpose: "public web tier", Prefix: netip.MustParsePrefix("10.0.0.0/17")}, plan.Sites[0].VPCs[0].Subnets[0])
+
+	assert.Equal(t, []LabeledPrefix{
+		labeled("fra1", "10.0.0.0/15"),
+		labeled("fra1/prod", "10.0.0.0/16"),
+		labeled("fra1/prod/web", "10.0.0.0/17"),
+		labeled("fra1/prod/db", "10.0.128.0/18"),
+		labeled("ams1", "10.2.0.0/16"),
+	}, plan.Prefixes())
+}
+
+func TestParsePlan_Errors(t *testing.T) {
+	_, err := ParsePlan([]byte("sites:\n  - name: a\n    prefix: 10.0.0.0/33\n"))
+	assert.ErrorContains(t, err, "invalid plan")
+
+	_, err = ParsePlan([]byte("sites:\n  - name: a\n    cidr: 10.0.0.0/8\n"))
+	assert.ErrorContains(t, err, "field cidr not found")
+}
+
+func TestValidatePlan_Valid(t *testing.T) {
+	plan, err := ParsePlan([]byte(validPlanYAML))
+	require.NoError(t, err)
+
+	report := ValidatePlan(plan)
+	assert.Empty(t, report.Findings)
+	assert.Equal(t, []PlanUtilization{
+		{Path: "fra1", Depth: 0, Prefix: netip.MustParsePrefix("10.0.0.0/15"), Utilization: 0.5},
+		{Path: "fra1/prod", Depth: 1, Prefix: netip.MustParsePrefix("10.0.0.0/16"), Utilization: 0.75},
+		{Path: "ams1", Depth: 0, Prefix: netip.MustParsePrefix("10.2.0.0/16"), Utilization: 0},
+	}, report.Utilization)
+}
+
+func TestValidatePlan_Findings(t *testing.T) {
+	plan := Plan{Sites: []PlanSite{
+		{
+			Name:   "fra1",
+			Prefix: netip.MustParsePrefix("10.0.0.0/16"),
+			VPCs: []PlanVPC{
+				{
+					Name:   "prod",
+					Prefix: netip.MustParsePrefix("10.0.0.0/20"),
+					Subnets: []PlanSubnet{
+						{Name: "web", Prefix: netip.MustParsePrefix("10.0.1.7/24")},
+						{Name: "app", Prefix: netip.MustParsePrefix("10.0.1.128/25")},
+						{Name: "stray", Prefix: netip.MustParsePrefix("10.0.16.0/24")},
+					},
+				},
+				{Name: "dev", Prefix: netip.MustParsePrefix("10.1.0.0/20")},
+				{Prefix: netip.MustParsePrefix("10.0.32.0/20")},
+			},
+		},
+		{Name: "ams1"},
+		{Name: "lab", Prefix: netip.MustParsePrefix("10.0.128.0/17")},
+	}}
+
+	report := ValidatePlan(plan)
+	assert.Equal(t, []PlanFinding{
+		{Path: "ams1", Problem: "missing prefix"},
+		{Path: "lab", Problem: "10.0.128.0/17 overlaps sibling fra1 (10.0.0.0/16)"},
+		{Path: "fra1/[2]", Problem: "missing name"},
+		{Path: "fra1/prod/web", Problem: "10.0.1.7/24 is not aligned; did you mean 10.0.1.0/24?"},
+		{Path: "fra1/prod/app", Problem: "10.0.1.128/25 overlaps sibling fra1/prod/web (10.0.1.0/24)"},
+		{Path: "fra1/prod/stray", Problem: "10.0.16.0/24 is not inside parent 10.0.0.0/20"},
+		{Path: "fra1/dev", Problem: "10.1.0.0/20 is not inside parent 10.0.0.0/16"},
+	}, report.Findings)
+}
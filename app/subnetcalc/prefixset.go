@@ -0,0 +1,236 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+// PrefixSet is an immutable set of IP addresses. It is stored as a sorted list
+// of non-overlapping address ranges, so every operation returns a normalized
+// set regardless of how the inputs overlapped.
+//
+// IPv4 and IPv6 addresses may be mixed in one set; the two families never
+// merge into each other.
+type PrefixSet struct {
+	ranges []ipRange
+}
+
+type ipRange struct {
+	from netip.Addr
+	to   netip.Addr
+}
+
+// NewPrefixSet returns the set of all addresses covered by prefixes.
+// Invalid prefixes are ignored and host bits are masked off.
+func NewPrefixSet(prefixes ...netip.Prefix) PrefixSet {
+	ranges := make([]ipRange, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if !prefix.IsValid() {
+			continue
+		}
+		ranges = append(ranges, prefixRange(prefix))
+	}
+	return PrefixSet{ranges: normalizeRanges(ranges)}
+}
+
+// Prefixes returns the set as the minimal list of CIDR prefixes, sorted by
+// address with IPv4 before IPv6.
+func (s PrefixSet) Prefixes() []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, r := range s.ranges {
+		prefixes = append(prefixes, rangePrefixes(r.from, r.to)...)
+	}
+	return prefixes
+}
+
+// IsEmpty reports whether the set contains no addresses.
+func (s PrefixSet) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+// Contains reports whether addr is in the set.
+func (s PrefixSet) Contains(addr netip.Addr) bool {
+	addr = addr.WithZone("")
+	for _, r := range s.ranges {
+		if r.from.Compare(addr) <= 0 && addr.Compare(r.to) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsPrefix reports whether every address of prefix is in the set.
+func (s PrefixSet) ContainsPrefix(prefix netip.Prefix) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	want := prefixRange(prefix)
+	for _, r := range s.ranges {
+		if r.from.Compare(want.from) <= 0 && want.to.Compare(r.to) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Union returns the addresses that are in s or o.
+func (s PrefixSet) Union(o PrefixSet) PrefixSet {
+	ranges := make([]ipRange, 0, len(s.ranges)+len(o.ranges))
+	ranges = append(ranges, s.ranges...)
+	ranges = append(ranges, o.ranges...)
+	return PrefixSet{ranges: normalizeRanges(ranges)}
+}
+
+// Intersect returns the addresses that are in both s and o.
+func (s PrefixSet) Intersect(o PrefixSet) PrefixSet {
+	var ranges []ipRange
+	i, j := 0, 0
+	for i < len(s.ranges) && j < len(o.ranges) {
+		a, b := s.ranges[i], o.ranges[j]
+		from := maxAddr(a.from, b.from)
+		to := minAddr(a.to, b.to)
+		if from.Compare(to) <= 0 {
+			ranges = append(ranges, ipRange{from: from, to: to})
+		}
+		if a.to.Compare(b.to) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return PrefixSet{ranges: ranges}
+}
+
+// Subtract returns the addresses that are in s but not in o.
+func (s PrefixSet) Subtract(o PrefixSet) PrefixSet {
+	var ranges []ipRange
+	for _, r := range s.ranges {
+		from := r.from
+		remaining := true
+		for _, cut := range o.ranges {
+			if cut.to.Compare(from) < 0 || cut.from.Compare(r.to) > 0 {
+				continue
+			}
+			if cut.from.Compare(from) > 0 {
+				ranges = append(ranges, ipRange{from: from, to: cut.from.Prev()})
+			}
+			if cut.to.Compare(r.to) >= 0 {
+				remaining = false
+				break
+			}
+			from = cut.to.Next()
+		}
+		if remaining {
+			ranges = append(ranges, ipRange{from: from, to: r.to})
+		}
+	}
+	return PrefixSet{ranges: ranges}
+}
+
+// Complement returns every IPv4 and IPv6 address that is not in s.
+func (s PrefixSet) Complement() PrefixSet {
+	all := NewPrefixSet(netip.MustParsePrefix("0.0.0.0/0"), netip.MustParsePrefix("::/0"))
+	return all.Subtract(s)
+}
+
+func prefixRange(prefix netip.Prefix) ipRange {
+	return ipRange{from: prefix.Masked().Addr(), to: lastAddr(prefix)}
+}
+
+// lastAddr returns the highest address covered by prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	prefix = prefix.Masked()
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// RangeToPrefixes returns the fewest CIDR prefixes that exactly cover the
+// inclusive address range from-to. Ranges such as the "start count" records
+// of RIR delegated-stats files are rarely aligned, so the result usually
+// holds prefixes of several lengths.
+//
+// Example:
+//
+//	RangeToPrefixes(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.6"))
+//	// [10.0.0.1/32 10.0.0.2/31 10.0.0.4/31 10.0.0.6/32]
+func RangeToPrefixes(from, to netip.Addr) ([]netip.Prefix, error) {
+	if !from.IsValid() || !to.IsValid() {
+		return nil, errors.New("invalid address")
+	}
+	if from.Is4() != to.Is4() {
+		return nil, fmt.Errorf("%s and %s are of different address families", from, to)
+	}
+	from, to = from.WithZone(""), to.WithZone("")
+	if from.Compare(to) > 0 {
+		return nil, fmt.Errorf("range start %s is after range end %s", from, to)
+	}
+	return rangePrefixes(from, to), nil
+}
+
+// rangePrefixes splits the inclusive range from-to into the fewest CIDR
+// prefixes that cover it exactly.
+func rangePrefixes(from, to netip.Addr) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for {
+		bits := from.BitLen()
+		for bits > 0 {
+			wider := netip.PrefixFrom(from, bits-1)
+			if wider.Masked().Addr() != from || lastAddr(wider).Compare(to) > 0 {
+				break
+			}
+			bits--
+		}
+		prefix := netip.PrefixFrom(from, bits)
+		prefixes = append(prefixes, prefix)
+		last := lastAddr(prefix)
+		if last == to {
+			return prefixes
+		}
+		from = last.Next()
+	}
+}
+
+// normalizeRanges sorts ranges and merges the ones that overlap or touch.
+func normalizeRanges(ranges []ipRange) []ipRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	ranges = slices.Clone(ranges)
+	slices.SortFunc(ranges, func(a, b ipRange) int { return a.from.Compare(b.from) })
+
+	merged := []ipRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.from.Is4() != last.to.Is4() {
+			merged = append(merged, r)
+			continue
+		}
+		next := last.to.Next()
+		if r.from.Compare(last.to) <= 0 || (next.IsValid() && r.from == next) {
+			last.to = maxAddr(last.to, r.to)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func minAddr(a, b netip.Addr) netip.Addr {
+	if a.Compare(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+func maxAddr(a, b netip.Addr) netip.Addr {
+	if a.Compare(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,184 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustPrefixes(t *testing.T, strs ...string) []netip.Prefix {
+	t.Helper()
+	prefixes := make([]netip.Prefix, 0, len(strs))
+	for _, s := range strs {
+		prefixes = append(prefixes, netip.MustParsePrefix(s))
+	}
+	return prefixes
+}
+
+func prefixStrings(prefixes []netip.Prefix) []string {
+	strs := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		strs = append(strs, p.String())
+	}
+	return strs
+}
+
+func ExamplePrefixSet_Subtract() {
+	all := NewPrefixSet(netip.MustParsePrefix("10.0.0.0/24"))
+	used := NewPrefixSet(netip.MustParsePrefix("10.0.0.64/26"))
+	fmt.Println(all.Subtract(used).Prefixes())
+	// Output: [10.0.0.0/26 10.0.0.128/25]
+}
+
+func TestNewPrefixSet_Normalizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"empty", nil, []string{}},
+		{"host bits masked", []string{"10.0.0.5/24"}, []string{"10.0.0.0/24"}},
+		{"adjacent halves merge", []string{"10.0.0.0/25", "10.0.0.128/25"}, []string{"10.0.0.0/24"}},
+		{"contained prefix absorbed", []string{"10.0.0.0/8", "10.1.2.0/24"}, []string{"10.0.0.0/8"}},
+		{"duplicates", []string{"192.168.1.0/24", "192.168.1.0/24"}, []string{"192.168.1.0/24"}},
+		{"unaligned merge", []string{"10.0.0.1/32", "10.0.0.2/31"}, []string{"10.0.0.1/32", "10.0.0.2/31"}},
+		{
+			"three quarters",
+			[]string{"10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/26"},
+			[]string{"10.0.0.0/25", "10.0.0.128/26"},
+		},
+		{"mixed families stay apart", []string{"2001:db8::/32", "10.0.0.0/8"}, []string{"10.0.0.0/8", "2001:db8::/32"}},
+		{"top of IPv4 does not touch IPv6", []string{"255.255.255.255/32", "::/128"}, []string{"255.255.255.255/32", "::/128"}},
+		{"ipv6 merge", []string{"2001:db8::/33", "2001:db8:8000::/33"}, []string{"2001:db8::/32"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPrefixSet(mustPrefixes(t, tt.input...)...)
+			assert.Equal(t, tt.want, prefixStrings(got.Prefixes()))
+		})
+	}
+}
+
+func TestNewPrefixSet_IgnoresInvalid(t *testing.T) {
+	got := NewPrefixSet(netip.Prefix{}, netip.MustParsePrefix("10.0.0.0/8"))
+	assert.Equal(t, []string{"10.0.0.0/8"}, prefixStrings(got.Prefixes()))
+}
+
+func TestPrefixSet_Union(t *testing.T) {
+	a := NewPrefixSet(mustPrefixes(t, "10.0.0.0/25", "10.0.1.0/24")...)
+	b := NewPrefixSet(mustPrefixes(t, "10.0.0.128/25", "2001:db8::/64")...)
+	got := a.Union(b)
+	assert.Equal(t, []string{"10.0.0.0/23", "2001:db8::/64"}, prefixStrings(got.Prefixes()))
+}
+
+func TestPrefixSet_Intersect(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want []string
+	}{
+		{"disjoint", []string{"10.0.0.0/24"}, []string{"10.0.1.0/24"}, []string{}},
+		{"nested", []string{"10.0.0.0/8"}, []string{"10.1.0.0/16", "11.0.0.0/8"}, []string{"10.1.0.0/16"}},
+		{
+			"partial overlap",
+			[]string{"10.0.0.0/25", "10.0.0.192/26"},
+			[]string{"10.0.0.64/26", "10.0.0.128/26", "10.0.0.192/27"},
+			[]string{"10.0.0.64/26", "10.0.0.192/27"},
+		},
+		{"families never intersect", []string{"0.0.0.0/0"}, []string{"::/0"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewPrefixSet(mustPrefixes(t, tt.a...)...)
+			b := NewPrefixSet(mustPrefixes(t, tt.b...)...)
+			assert.Equal(t, tt.want, prefixStrings(a.Intersect(b).Prefixes()))
+			assert.Equal(t, tt.want, prefixStrings(b.Intersect(a).Prefixes()))
+		})
+	}
+}
+
+func TestPrefixSet_Subtract(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want []string
+	}{
+		{"nothing removed", []string{"10.0.0.0/24"}, []string{"10.0.1.0/24"}, []string{"10.0.0.0/24"}},
+		{"everything removed", []string{"10.0.0.0/24"}, []string{"10.0.0.0/16"}, []string{}},
+		{"hole in the middle", []string{"10.0.0.0/24"}, []string{"10.0.0.64/26"}, []string{"10.0.0.0/26", "10.0.0.128/25"}},
+		{
+			"single host",
+			[]string{"192.168.0.0/29"},
+			[]string{"192.168.0.3/32"},
+			[]string{"192.168.0.0/31", "192.168.0.2/32", "192.168.0.4/30"},
+		},
+		{
+			"several cuts",
+			[]string{"10.0.0.0/24"},
+			[]string{"10.0.0.0/26", "10.0.0.128/26"},
+			[]string{"10.0.0.64/26", "10.0.0.192/26"},
+		},
+		{"ipv6 top end", []string{"ffff::/16"}, []string{"ffff:8000::/17"}, []string{"ffff::/17"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewPrefixSet(mustPrefixes(t, tt.a...)...)
+			b := NewPrefixSet(mustPrefixes(t, tt.b...)...)
+			assert.Equal(t, tt.want, prefixStrings(a.Subtract(b).Prefixes()))
+		})
+	}
+}
+
+func TestPrefixSet_Complement(t *testing.T) {
+	got := NewPrefixSet(mustPrefixes(t, "0.0.0.0/1", "::/1")...).Complement()
+	assert.Equal(t, []string{"128.0.0.0/1", "8000::/1"}, prefixStrings(got.Prefixes()))
+
+	empty := NewPrefixSet()
+	assert.Equal(t, []string{"0.0.0.0/0", "::/0"}, prefixStrings(empty.Complement().Prefixes()))
+	assert.True(t, empty.Complement().Complement().IsEmpty())
+}
+
+func TestPrefixSet_Contains(t *testing.T) {
+	s := NewPrefixSet(mustPrefixes(t, "10.0.0.0/24", "2001:db8::/32")...)
+
+	assert.True(t, s.Contains(netip.MustParseAddr("10.0.0.0")))
+	assert.True(t, s.Contains(netip.MustParseAddr("10.0.0.255")))
+	assert.False(t, s.Contains(netip.MustParseAddr("10.0.1.0")))
+	assert.True(t, s.Contains(netip.MustParseAddr("2001:db8::1")))
+	assert.False(t, s.Contains(netip.MustParseAddr("::ffff:10.0.0.1")))
+
+	assert.True(t, s.ContainsPrefix(netip.MustParsePrefix("10.0.0.128/25")))
+	assert.False(t, s.ContainsPrefix(netip.MustParsePrefix("10.0.0.0/23")))
+	assert.False(t, s.ContainsPrefix(netip.Prefix{}))
+}
+
+func TestRangeToPrefixes(t *testing.T) {
+	prefixes, err := RangeToPrefixes(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.6"))
+	require.NoError(t, err)
+	assert.Equal(t, mustPrefixes(t, "10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32"), prefixes)
+
+	prefixes, err = RangeToPrefixes(netip.MustParseAddr("0.0.0.0"), netip.MustParseAddr("255.255.255.255"))
+	require.NoError(t, err)
+	assert.Equal(t, mustPrefixes(t, "0.0.0.0/0"), prefixes)
+
+	prefixes, err = RangeToPrefixes(netip.MustParseAddr("2001:db8::"), netip.MustParseAddr("2001:db8::1:ffff"))
+	require.NoError(t, err)
+	assert.Equal(t, mustPrefixes(t, "2001:db8::/111"), prefixes)
+
+	_, err = RangeToPrefixes(netip.MustParseAddr("10.0.0.9"), netip.MustParseAddr("10.0.0.1"))
+	assert.EqualError(t, err, "range start 10.0.0.9 is after range end 10.0.0.1")
+
+	_, err = RangeToPrefixes(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1"))
+	assert.EqualError(t, err, "10.0.0.1 and ::1 are of different address families")
+
+	_, err = RangeToPrefixes(netip.Addr{}, netip.MustParseAddr("::1"))
+	assert.EqualError(t, err, "invalid address")
+}
@@ -0,0 +1,142 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Provider identifies a cloud provider whose subnets reserve addresses.
+type Provider string
+
+// Supported cloud providers.
+const (
+	ProviderAWS   Provider = "aws"
+	ProviderAzure Provider = "azure"
+	ProviderGCP   Provider = "gcp"
+)
+
+// Providers lists every provider CalcProviderSubnetInfo understands.
+var Providers = []Provider{ProviderAWS, ProviderAzure, ProviderGCP}
+
+// ReservedAddress is an address of a subnet that cannot be assigned to hosts.
+type ReservedAddress struct {
+	Address netip.Addr `json:"address" yaml:"address"`
+	Reason  string     `json:"reason" yaml:"reason"`
+}
+
+// providerRule describes the reserved addresses and allowed IPv4 prefix
+// lengths of a provider's subnets. Offsets are counted from the network
+// address when non-negative and back from the broadcast address otherwise.
+type providerRule struct {
+	minBits  int
+	maxBits  int
+	reserved []reservedOffset
+}
+
+type reservedOffset struct {
+	offset int
+	reason string
+}
+
+var providerRules = map[Provider]providerRule{
+	ProviderAWS: {
+		minBits: 16,
+		maxBits: 28,
+		reserved: []reservedOffset{
+			{0, "network address"},
+			{1, "VPC router"},
+			{2, "Amazon-provided DNS"},
+			{3, "reserved for future use"},
+			{-1, "broadcast address (not supported in a VPC)"},
+		},
+	},
+	ProviderAzure: {
+		minBits: 2,
+		maxBits: 29,
+		reserved: []reservedOffset{
+			{0, "network address"},
+			{1, "default gateway"},
+			{2, "Azure DNS"},
+			{3, "Azure DNS"},
+			{-1, "broadcast address"},
+		},
+	},
+	ProviderGCP: {
+		minBits: 4,
+		maxBits: 29,
+		reserved: []reservedOffset{
+			{0, "network address"},
+			{1, "default gateway"},
+			{-2, "reserved for future use"},
+			{-1, "broadcast address"},
+		},
+	},
+}
+
+// ParseProvider parses a provider name such as "aws".
+func ParseProvider(name string) (Provider, error) {
+	provider := Provider(strings.ToLower(name))
+	if _, ok := providerRules[provider]; !ok {
+		return "", fmt.Errorf("unknown provider %q", name)
+	}
+	return provider, nil
+}
+
+// Allows reports whether the provider allows prefix as a subnet: an IPv4
+// prefix within its range of prefix lengths.
+func (p Provider) Allows(prefix netip.Prefix) bool {
+	rule, ok := providerRules[p]
+	if !ok || !prefix.Addr().Is4() {
+		return false
+	}
+	return prefix.Bits() >= rule.minBits && prefix.Bits() <= rule.maxBits
+}
+
+// CalcProviderSubnetInfo calculates subnet information for prefix as a subnet
+// of the given cloud provider. The result lists the addresses the provider
+// reserves, which UsableHosts subtracts from the total.
+//
+// An error is returned if the provider does not allow subnets of this size,
+// for example an AWS subnet outside /16 to /28.
+func CalcProviderSubnetInfo(prefix netip.Prefix, provider Provider) (SubnetInfo, error) {
+	result, err := NewCalculator(WithProvider(provider)).Calculate(prefix)
+	if err != nil {
+		return SubnetInfo{}, err
+	}
+	return result.SubnetInfo(), nil
+}
+
+// providerReserved returns the addresses provider reserves in info, or an
+// error if the provider does not allow subnets of this size.
+func providerReserved(prefix netip.Prefix, info SubnetInfo, provider Provider) ([]ReservedAddress, error) {
+	rule, ok := providerRules[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+	if prefix.Bits() < rule.minBits || prefix.Bits() > rule.maxBits {
+		return nil, fmt.Errorf("%s subnets must be between /%d and /%d, got /%d", provider, rule.minBits, rule.maxBits, prefix.Bits())
+	}
+
+	reserved := make([]ReservedAddress, 0, len(rule.reserved))
+	for _, r := range rule.reserved {
+		reserved = append(reserved, ReservedAddress{Address: offsetAddr(info, r.offset), Reason: r.reason})
+	}
+	return reserved, nil
+}
+
+// offsetAddr returns the address offset steps into the subnet. The prefix
+// length limits guarantee every offset stays inside it.
+func offsetAddr(info SubnetInfo, offset int) netip.Addr {
+	addr := info.NetworkAddress
+	step := netip.Addr.Next
+	if offset < 0 {
+		addr = info.BroadcastIP
+		step = netip.Addr.Prev
+		offset = -offset - 1
+	}
+	for range offset {
+		addr = step(addr)
+	}
+	return addr
+}
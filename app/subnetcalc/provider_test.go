@@ -0,0 +1,120 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleCalcProviderSubnetInfo() {
+	info, _ := CalcProviderSubnetInfo(netip.MustParsePrefix("10.0.1.0/24"), ProviderAWS)
+	fmt.Println(info.TotalIP, info.UsableHosts())
+	for _, r := range info.Reserved {
+		fmt.Println(r.Address, r.Reason)
+	}
+	// Output:
+	// 256 251
+	// 10.0.1.0 network address
+	// 10.0.1.1 VPC router
+	// 10.0.1.2 Amazon-provided DNS
+	// 10.0.1.3 reserved for future use
+	// 10.0.1.255 broadcast address (not supported in a VPC)
+}
+
+func TestSubnetInfo_UsableHosts(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint
+	}{
+		{"10.0.0.0/8", 16777214},
+		{"192.168.1.0/24", 254},
+		{"192.168.1.0/30", 2},
+		{"192.168.1.0/31", 2},
+		{"192.168.1.1/32", 1},
+		{"0.0.0.0/0", 4294967294},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			info, err := CalcSubnetInfo(netip.MustParsePrefix(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, info.UsableHosts())
+		})
+	}
+}
+
+func TestCalcProviderSubnetInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		provider   Provider
+		wantUsable uint
+		wantAddrs  []string
+	}{
+		{"aws /28", "10.0.0.16/28", ProviderAWS, 11, []string{"10.0.0.16", "10.0.0.17", "10.0.0.18", "10.0.0.19", "10.0.0.31"}},
+		{"aws /16", "10.1.0.0/16", ProviderAWS, 65531, []string{"10.1.0.0", "10.1.0.1", "10.1.0.2", "10.1.0.3", "10.1.255.255"}},
+		{"azure /29", "10.0.0.8/29", ProviderAzure, 3, []string{"10.0.0.8", "10.0.0.9", "10.0.0.10", "10.0.0.11", "10.0.0.15"}},
+		{"gcp /24", "10.2.0.0/24", ProviderGCP, 252, []string{"10.2.0.0", "10.2.0.1", "10.2.0.254", "10.2.0.255"}},
+		{"gcp /29", "10.2.0.0/29", ProviderGCP, 4, []string{"10.2.0.0", "10.2.0.1", "10.2.0.6", "10.2.0.7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := CalcProviderSubnetInfo(netip.MustParsePrefix(tt.input), tt.provider)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUsable, info.UsableHosts())
+
+			addrs := make([]string, 0, len(info.Reserved))
+			for _, r := range info.Reserved {
+				addrs = append(addrs, r.Address.String())
+				assert.NotEmpty(t, r.Reason)
+			}
+			assert.Equal(t, tt.wantAddrs, addrs)
+		})
+	}
+}
+
+func TestCalcProviderSubnetInfo_PrefixLimits(t *testing.T) {
+	tests := []struct {
+		input    string
+		provider Provider
+		wantErr  string
+	}{
+		{"10.0.0.0/15", ProviderAWS, "aws subnets must be between /16 and /28, got /15"},
+		{"10.0.0.0/29", ProviderAWS, "aws subnets must be between /16 and /28, got /29"},
+		{"10.0.0.0/30", ProviderAzure, "azure subnets must be between /2 and /29, got /30"},
+		{"10.0.0.0/30", ProviderGCP, "gcp subnets must be between /4 and /29, got /30"},
+		{"10.0.0.0/24", "oracle", `unknown provider "oracle"`},
+		{"2001:db8::/64", ProviderAWS, "IPv6 not supported yet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.provider, tt.input), func(t *testing.T) {
+			info, err := CalcProviderSubnetInfo(netip.MustParsePrefix(tt.input), tt.provider)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Equal(t, SubnetInfo{}, info)
+		})
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	provider, err := ParseProvider("AWS")
+	require.NoError(t, err)
+	assert.Equal(t, ProviderAWS, provider)
+
+	_, err = ParseProvider("digitalocean")
+	assert.EqualError(t, err, `unknown provider "digitalocean"`)
+}
+
+func TestProvider_Allows(t *testing.T) {
+	assert.True(t, ProviderAWS.Allows(netip.MustParsePrefix("10.0.0.0/16")))
+	assert.True(t, ProviderAWS.Allows(netip.MustParsePrefix("10.0.0.0/28")))
+	assert.False(t, ProviderAWS.Allows(netip.MustParsePrefix("10.0.0.0/29")))
+	assert.False(t, ProviderAWS.Allows(netip.MustParsePrefix("10.0.0.0/8")))
+	assert.True(t, ProviderAzure.Allows(netip.MustParsePrefix("10.0.0.0/29")))
+	assert.False(t, ProviderAWS.Allows(netip.MustParsePrefix("2001:db8::/64")))
+	assert.False(t, Provider("oracle").Allows(netip.MustParsePrefix("10.0.0.0/24")))
+}
@@ -0,0 +1,266 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// maxZoneFileHosts caps the number of PTR records ReverseZoneFiles will generate.
+const maxZoneFileHosts = 1 << 16
+
+// ResourceRecord is a single DNS record with a fully qualified owner name.
+type ResourceRecord struct {
+	Name  string `json:"name" yaml:"name"`
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// ReverseZone is a reverse DNS zone and the part of the prefix it covers.
+type ReverseZone struct {
+	Name   string       `json:"name" yaml:"name"`
+	Prefix netip.Prefix `json:"prefix" yaml:"prefix"`
+}
+
+// ClasslessDelegation describes an RFC 2317 delegation of an IPv4 block
+// smaller than a /24. The Records belong in ParentZone and point every
+// address of the block at the delegated Zone.
+type ClasslessDelegation struct {
+	ParentZone string           `json:"parent_zone" yaml:"parent_zone"`
+	Zone       string           `json:"zone" yaml:"zone"`
+	Records    []ResourceRecord `json:"records" yaml:"records"`
+}
+
+// ReverseDNSInfo lists the reverse zones needed to serve PTR records for a prefix.
+type ReverseDNSInfo struct {
+	Zones     []ReverseZone        `json:"zones" yaml:"zones"`
+	Classless *ClasslessDelegation `json:"classless,omitempty" yaml:"classless,omitempty"`
+}
+
+// ZoneFileOptions controls the skeleton written by ReverseZoneFiles.
+//
+// HostTemplate is expanded once per address. It understands {ip} (the address
+// with '.' and ':' replaced by '-'), {a} {b} {c} {d} (the IPv4 octets) and {n}
+// (the offset of the address from the network address).
+type ZoneFileOptions struct {
+	HostTemplate string
+	NameServer   string
+	Contact      string
+	TTL          uint32
+}
+
+// ZoneFile is a BIND-format reverse zone.
+type ZoneFile struct {
+	Zone       string
+	NameServer string
+	Contact    string
+	TTL        uint32
+	Records    []ResourceRecord
+}
+
+// ReverseName returns the in-addr.arpa or ip6.arpa name of addr.
+//
+// Example:
+//
+//	ReverseName(netip.MustParseAddr("192.0.2.1")) // "1.2.0.192.in-addr.arpa."
+func ReverseName(addr netip.Addr) string {
+	return reverseZoneName(netip.PrefixFrom(addr, addr.BitLen()))
+}
+
+// ReverseDNS returns the reverse zones that cover prefix.
+//
+// Octet-aligned IPv4 prefixes and nibble-aligned IPv6 prefixes map to a single
+// zone. Other prefixes are covered by the zones at the next boundary down, so a
+// /20 needs sixteen /24 zones. IPv4 prefixes longer than /24 get an RFC 2317
+// classless zone plus the CNAMEs the /24 parent must publish.
+func ReverseDNS(prefix netip.Prefix) (ReverseDNSInfo, error) {
+	if !prefix.IsValid() {
+		return ReverseDNSInfo{}, errors.New("invalid prefix")
+	}
+	prefix = prefix.Masked()
+
+	if prefix.Addr().Is4() && prefix.Bits() > 24 {
+		return classlessReverseDNS(prefix)
+	}
+
+	unit := 4
+	if prefix.Addr().Is4() {
+		unit = 8
+	}
+	boundary := (prefix.Bits() + unit - 1) / unit * unit
+
+	var info ReverseDNSInfo
+	for _, sub := range subdivide(prefix, boundary) {
+		info.Zones = append(info.Zones, ReverseZone{Name: reverseZoneName(sub), Prefix: sub})
+	}
+	return info, nil
+}
+
+func classlessReverseDNS(prefix netip.Prefix) (ReverseDNSInfo, error) {
+	subnet, err := CalcSubnetInfo(prefix)
+	if err != nil {
+		return ReverseDNSInfo{}, err
+	}
+	parent := reverseZoneName(netip.PrefixFrom(subnet.NetworkAddress, 24).Masked())
+	first := subnet.NetworkAddress.As4()[3]
+	zone := fmt.Sprintf("%d/%d.%s", first, prefix.Bits(), parent)
+
+	delegation := &ClasslessDelegation{ParentZone: parent, Zone: zone}
+	for addr := subnet.NetworkAddress; ; addr = addr.Next() {
+		delegation.Records = append(delegation.Records, ResourceRecord{
+			Name:  ReverseName(addr),
+			Type:  "CNAME",
+			Value: classlessPTRName(addr, zone),
+		})
+		if addr == subnet.BroadcastIP {
+			break
+		}
+	}
+
+	return ReverseDNSInfo{
+		Zones:     []ReverseZone{{Name: zone, Prefix: prefix}},
+		Classless: delegation,
+	}, nil
+}
+
+// ReverseZoneFiles builds a BIND-format skeleton for every zone returned by
+// ReverseDNS, with one PTR record per host address of prefix. For IPv4
+// prefixes of /30 and shorter the network and broadcast addresses are skipped.
+func ReverseZoneFiles(prefix netip.Prefix, opts ZoneFileOptions) ([]ZoneFile, error) {
+	info, err := ReverseDNS(prefix)
+	if err != nil {
+		return nil, err
+	}
+	prefix = prefix.Masked()
+	if prefix.Addr().BitLen()-prefix.Bits() > 16 {
+		return nil, fmt.Errorf("prefix %s has more than %d addresses", prefix, maxZoneFileHosts)
+	}
+	opts = opts.withDefaults()
+
+	skipEnds := prefix.Addr().Is4() && prefix.Bits() <= 30
+	network, broadcast := prefix.Addr(), lastAddr(prefix)
+
+	files := make([]ZoneFile, 0, len(info.Zones))
+	for _, zone := range info.Zones {
+		file := ZoneFile{Zone: zone.Name, NameServer: opts.NameServer, Contact: opts.Contact, TTL: opts.TTL}
+		last := lastAddr(zone.Prefix)
+		for addr := zone.Prefix.Addr(); ; addr = addr.Next() {
+			if !skipEnds || (addr != network && addr != broadcast) {
+				owner := ReverseName(addr)
+				if info.Classless != nil {
+					owner = classlessPTRName(addr, zone.Name)
+				}
+				file.Records = append(file.Records, ResourceRecord{
+					Name:  owner,
+					Type:  "PTR",
+					Value: expandHostTemplate(opts.HostTemplate, network, addr),
+				})
+			}
+			if addr == last {
+				break
+			}
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// String renders the zone in BIND master file format.
+func (z ZoneFile) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s\n", z.Zone)
+	fmt.Fprintf(&b, "$TTL %d\n", z.TTL)
+	fmt.Fprintf(&b, "@\tIN\tSOA\t%s %s (\n", z.NameServer, z.Contact)
+	b.WriteString("\t\t\t1\t; serial\n")
+	b.WriteString("\t\t\t3600\t; refresh\n")
+	b.WriteString("\t\t\t900\t; retry\n")
+	b.WriteString("\t\t\t604800\t; expire\n")
+	b.WriteString("\t\t\t86400 )\t; negative caching TTL\n")
+	fmt.Fprintf(&b, "@\tIN\tNS\t%s\n", z.NameServer)
+	for _, rr := range z.Records {
+		fmt.Fprintf(&b, "%s\tIN\t%s\t%s\n", rr.Name, rr.Type, rr.Value)
+	}
+	return b.String()
+}
+
+func (o ZoneFileOptions) withDefaults() ZoneFileOptions {
+	if o.HostTemplate == "" {
+		o.HostTemplate = "host-{ip}.example.com."
+	}
+	if o.NameServer == "" {
+		o.NameServer = "ns1.example.com."
+	}
+	if o.Contact == "" {
+		o.Contact = "hostmaster.example.com."
+	}
+	if o.TTL == 0 {
+		o.TTL = 3600
+	}
+	return o
+}
+
+func expandHostTemplate(template string, network, addr netip.Addr) string {
+	dashed := strings.NewReplacer(".", "-", ":", "-").Replace(addr.String())
+	pairs := []string{"{ip}", dashed, "{n}", addrOffset(network, addr)}
+	if addr.Is4() {
+		octets := addr.As4()
+		pairs = append(pairs,
+			"{a}", strconv.Itoa(int(octets[0])),
+			"{b}", strconv.Itoa(int(octets[1])),
+			"{c}", strconv.Itoa(int(octets[2])),
+			"{d}", strconv.Itoa(int(octets[3])),
+		)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// addrOffset returns addr-base for addresses at most 2^16 apart.
+func addrOffset(base, addr netip.Addr) string {
+	b, a := base.As16(), addr.As16()
+	offset := (int(a[14])<<8 | int(a[15])) - (int(b[14])<<8 | int(b[15]))
+	return strconv.Itoa(offset)
+}
+
+func classlessPTRName(addr netip.Addr, zone string) string {
+	return fmt.Sprintf("%d.%s", addr.As4()[3], zone)
+}
+
+// reverseZoneName returns the reverse zone of an octet-aligned IPv4 or a
+// nibble-aligned IPv6 prefix.
+func reverseZoneName(prefix netip.Prefix) string {
+	addr := prefix.Addr()
+	var labels []string
+	if addr.Is4() {
+		octets := addr.As4()
+		for i := prefix.Bits()/8 - 1; i >= 0; i-- {
+			labels = append(labels, strconv.Itoa(int(octets[i])))
+		}
+		labels = append(labels, "in-addr.arpa.")
+	} else {
+		bytes := addr.As16()
+		for i := prefix.Bits()/4 - 1; i >= 0; i-- {
+			nibble := bytes[i/2] >> (4 * (1 - i%2)) & 0xf
+			labels = append(labels, strconv.FormatUint(uint64(nibble), 16))
+		}
+		labels = append(labels, "ip6.arpa.")
+	}
+	return strings.Join(labels, ".")
+}
+
+// subdivide splits prefix into the consecutive prefixes of length bits.
+func subdivide(prefix netip.Prefix, bits int) []netip.Prefix {
+	prefix = prefix.Masked()
+	last := lastAddr(prefix)
+	var subs []netip.Prefix
+	for addr := prefix.Addr(); ; {
+		sub := netip.PrefixFrom(addr, bits)
+		subs = append(subs, sub)
+		subLast := lastAddr(sub)
+		if subLast == last {
+			return subs
+		}
+		addr = subLast.Next()
+	}
+}
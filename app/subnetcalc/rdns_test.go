@@ -0,0 +1,145 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleReverseDNS() {
+	info, _ := ReverseDNS(netip.MustParsePrefix("172.16.38.94/27"))
+	fmt.Println(info.Zones[0].Name, info.Classless.ParentZone)
+	fmt.Println(info.Classless.Records[0].Name, info.Classless.Records[0].Value)
+	// Output:
+	// 64/27.38.16.172.in-addr.arpa. 38.16.172.in-addr.arpa.
+	// 64.38.16.172.in-addr.arpa. 64.64/27.38.16.172.in-addr.arpa.
+}
+
+func TestReverseName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"192.0.2.1", "1.2.0.192.in-addr.arpa."},
+		{"10.0.0.0", "0.0.0.10.in-addr.arpa."},
+		{"2001:db8::567:89ab", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, ReverseName(netip.MustParseAddr(tt.input)))
+		})
+	}
+}
+
+func TestReverseDNS_Zones(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"0.0.0.0/0", []string{"in-addr.arpa."}},
+		{"10.0.0.0/8", []string{"10.in-addr.arpa."}},
+		{"192.168.0.0/16", []string{"168.192.in-addr.arpa."}},
+		{"192.168.1.77/24", []string{"1.168.192.in-addr.arpa."}},
+		{"192.168.4.0/22", []string{"4.168.192.in-addr.arpa.", "5.168.192.in-addr.arpa.", "6.168.192.in-addr.arpa.", "7.168.192.in-addr.arpa."}},
+		{"172.16.0.0/15", []string{"16.172.in-addr.arpa.", "17.172.in-addr.arpa."}},
+		{"2001:db8::/32", []string{"8.b.d.0.1.0.0.2.ip6.arpa."}},
+		{"2001:db8:ab00::/40", []string{"b.a.8.b.d.0.1.0.0.2.ip6.arpa."}},
+		{"2001:db8::/31", []string{"8.b.d.0.1.0.0.2.ip6.arpa.", "9.b.d.0.1.0.0.2.ip6.arpa."}},
+		{"::/0", []string{"ip6.arpa."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			info, err := ReverseDNS(netip.MustParsePrefix(tt.input))
+			require.NoError(t, err)
+			assert.Nil(t, info.Classless)
+			names := make([]string, 0, len(info.Zones))
+			for _, zone := range info.Zones {
+				names = append(names, zone.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestReverseDNS_Classless(t *testing.T) {
+	info, err := ReverseDNS(netip.MustParsePrefix("192.0.2.128/30"))
+	require.NoError(t, err)
+	require.NotNil(t, info.Classless)
+
+	assert.Equal(t, []ReverseZone{{Name: "128/30.2.0.192.in-addr.arpa.", Prefix: netip.MustParsePrefix("192.0.2.128/30")}}, info.Zones)
+	assert.Equal(t, "2.0.192.in-addr.arpa.", info.Classless.ParentZone)
+	assert.Equal(t, []ResourceRecord{
+		{Name: "128.2.0.192.in-addr.arpa.", Type: "CNAME", Value: "128.128/30.2.0.192.in-addr.arpa."},
+		{Name: "129.2.0.192.in-addr.arpa.", Type: "CNAME", Value: "129.128/30.2.0.192.in-addr.arpa."},
+		{Name: "130.2.0.192.in-addr.arpa.", Type: "CNAME", Value: "130.128/30.2.0.192.in-addr.arpa."},
+		{Name: "131.2.0.192.in-addr.arpa.", Type: "CNAME", Value: "131.128/30.2.0.192.in-addr.arpa."},
+	}, info.Classless.Records)
+}
+
+func TestReverseDNS_InvalidPrefix(t *testing.T) {
+	_, err := ReverseDNS(netip.Prefix{})
+	assert.EqualError(t, err, "invalid prefix")
+}
+
+func TestReverseZoneFiles(t *testing.T) {
+	files, err := ReverseZoneFiles(netip.MustParsePrefix("192.0.2.128/30"), ZoneFileOptions{HostTemplate: "h{n}-{d}.example.net."})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	want := `$ORIGIN 128/30.2.0.192.in-addr.arpa.
+$TTL 3600
+@	IN	SOA	ns1.example.com. hostmaster.example.com. (
+			1	; serial
+			3600	; refresh
+			900	; retry
+			604800	; expire
+			86400 )	; negative caching TTL
+@	IN	NS	ns1.example.com.
+129.128/30.2.0.192.in-addr.arpa.	IN	PTR	h1-129.example.net.
+130.128/30.2.0.192.in-addr.arpa.	IN	PTR	h2-130.example.net.
+`
+	assert.Equal(t, want, files[0].String())
+}
+
+func TestReverseZoneFiles_PointToPoint(t *testing.T) {
+	files, err := ReverseZoneFiles(netip.MustParsePrefix("10.1.2.0/31"), ZoneFileOptions{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, []ResourceRecord{
+		{Name: "0.0/31.2.1.10.in-addr.arpa.", Type: "PTR", Value: "host-10-1-2-0.example.com."},
+		{Name: "1.0/31.2.1.10.in-addr.arpa.", Type: "PTR", Value: "host-10-1-2-1.example.com."},
+	}, files[0].Records)
+}
+
+func TestReverseZoneFiles_SplitAcrossZones(t *testing.T) {
+	files, err := ReverseZoneFiles(netip.MustParsePrefix("10.0.0.0/23"), ZoneFileOptions{})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "0.0.10.in-addr.arpa.", files[0].Zone)
+	assert.Len(t, files[0].Records, 255)
+	assert.Equal(t, "1.0.0.10.in-addr.arpa.", files[0].Records[0].Name)
+	assert.Equal(t, "1.0.10.in-addr.arpa.", files[1].Zone)
+	assert.Len(t, files[1].Records, 255)
+	assert.Equal(t, "254.1.0.10.in-addr.arpa.", files[1].Records[254].Name)
+}
+
+func TestReverseZoneFiles_IPv6(t *testing.T) {
+	files, err := ReverseZoneFiles(netip.MustParsePrefix("2001:db8::/124"), ZoneFileOptions{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Len(t, files[0].Records, 16)
+	assert.Equal(t, "host-2001-db8--3.example.com.", files[0].Records[3].Value)
+}
+
+func TestReverseZoneFiles_TooLarge(t *testing.T) {
+	_, err := ReverseZoneFiles(netip.MustParsePrefix("10.0.0.0/15"), ZoneFileOptions{})
+	assert.EqualError(t, err, "prefix 10.0.0.0/15 has more than 65536 addresses")
+
+	_, err = ReverseZoneFiles(netip.MustParsePrefix("2001:db8::/64"), ZoneFileOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package subnetcalc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DelegatedRecord is an IPv4 or IPv6 record of an RIR delegated-stats file
+// (the "delegated-<registry>-latest" files published by every RIR).
+//
+// IPv4 records give a start address and an address count that need not be a
+// power of two or aligned, so Prefixes may hold several CIDRs. ASN records,
+// the version header and summary lines are skipped.
+type DelegatedRecord struct {
+	Registry string         `json:"registry" yaml:"registry"`
+	Country  string         `json:"country" yaml:"country"`
+	Type     string         `json:"type" yaml:"type"`
+	Date     string         `json:"date" yaml:"date"`
+	Status   string         `json:"status" yaml:"status"`
+	Prefixes []netip.Prefix `json:"prefixes" yaml:"prefixes"`
+}
+
+// ParseDelegatedStats reads an RIR delegated-stats file, in either the
+// standard or the extended format.
+func ParseDelegatedStats(r io.Reader) ([]DelegatedRecord, error) {
+	var records []DelegatedRecord
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "|")
+		if len(fields) < 7 {
+			// Summary lines have six fields. A short first line is
+			// tolerated as a header; the standard version line has seven
+			// fields and is skipped by the record type switch below.
+			if line == 1 || len(fields) == 6 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: expected at least 7 fields, got %d", line, len(fields))
+		}
+		if fields[1] == "*" || fields[5] == "summary" {
+			continue
+		}
+
+		record := DelegatedRecord{
+			Registry: fields[0],
+			Country:  fields[1],
+			Type:     fields[2],
+			Date:     fields[5],
+			Status:   fields[6],
+		}
+		var err error
+		switch record.Type {
+		case "ipv4":
+			record.Prefixes, err = delegatedIPv4(fields[3], fields[4])
+		case "ipv6":
+			record.Prefixes, err = delegatedIPv6(fields[3], fields[4])
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func delegatedIPv4(start, value string) ([]netip.Prefix, error) {
+	from, err := netip.ParseAddr(start)
+	if err != nil || !from.Is4() {
+		return nil, fmt.Errorf("invalid IPv4 start address %q", start)
+	}
+	count, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || count == 0 || uint64(addrToUint32(from))+count > 1<<32 {
+		return nil, fmt.Errorf("invalid IPv4 address count %q", value)
+	}
+	to := uint32ToAddr(uint32(uint64(addrToUint32(from)) + count - 1))
+	return RangeToPrefixes(from, to)
+}
+
+func delegatedIPv6(start, value string) ([]netip.Prefix, error) {
+	addr, err := netip.ParseAddr(start)
+	if err != nil || !addr.Is6() {
+		return nil, fmt.Errorf("invalid IPv6 start address %q", start)
+	}
+	bits, err := strconv.Atoi(value)
+	if err != nil || bits < 0 || bits > 128 {
+		return nil, fmt.Errorf("invalid IPv6 prefix length %q", value)
+	}
+	return []netip.Prefix{netip.PrefixFrom(addr, bits).Masked()}, nil
+}
+
+// DelegatedGrouping selects the key SummarizeDelegated groups records by.
+type DelegatedGrouping string
+
+// Supported groupings.
+const (
+	GroupByCountry  DelegatedGrouping = "country"
+	GroupByRegistry DelegatedGrouping = "registry"
+)
+
+// DelegatedGroupings lists every grouping SummarizeDelegated understands.
+var DelegatedGroupings = []DelegatedGrouping{GroupByCountry, GroupByRegistry}
+
+// DelegatedSummary aggregates the records of one country or registry.
+// Prefixes holds their space merged into the fewest CIDRs, IPv4 first;
+// IPv4Addresses and IPv6Prefixes are counted from those merged prefixes.
+type DelegatedSummary struct {
+	Key           string         `json:"key" yaml:"key"`
+	Records       int            `json:"records" yaml:"records"`
+	IPv4Addresses uint64         `json:"ipv4_addresses" yaml:"ipv4_addresses"`
+	IPv6Prefixes  int            `json:"ipv6_prefixes" yaml:"ipv6_prefixes"`
+	Prefixes      []netip.Prefix `json:"prefixes" yaml:"prefixes"`
+}
+
+// SummarizeDelegated groups records by country or registry, sorted by key.
+func SummarizeDelegated(records []DelegatedRecord, by DelegatedGrouping) ([]DelegatedSummary, error) {
+	groups := make(map[string][]DelegatedRecord)
+	for _, r := range records {
+		var key string
+		switch by {
+		case GroupByCountry:
+			key = r.Country
+		case GroupByRegistry:
+			key = r.Registry
+		default:
+			return nil, fmt.Errorf("unknown grouping %q", by)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	summaries := make([]DelegatedSummary, 0, len(keys))
+	for _, key := range keys {
+		summary := DelegatedSummary{Key: key, Records: len(groups[key])}
+		var prefixes []netip.Prefix
+		for _, r := range groups[key] {
+			prefixes = append(prefixes, r.Prefixes...)
+		}
+		summary.Prefixes = NewPrefixSet(prefixes...).Prefixes()
+		for _, p := range summary.Prefixes {
+			if p.Addr().Is4() {
+				summary.IPv4Addresses += 1 << (32 - p.Bits())
+			} else {
+				summary.IPv6Prefixes++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
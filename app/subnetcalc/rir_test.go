@@ -0,0 +1,77 @@
+package subnetcalc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const delegatedSample = `2|ripencc|1700000000|4|19830705|20231114|+0100
+# comment
+ripencc|*|ipv4|*|3|summary
+ripencc|*|asn|*|1|summary
+ripencc|FR|ipv4|2.0.0.0|1048576|20100712|allocated
+ripencc|NL|ipv4|192.0.2.0|768|20200101|assigned|a1b2c3
+ripencc|EU|asn|64500|1|20050101|allocated
+ripencc|DE|ipv6|2001:db8::|32|20050101|allocated
+ripencc||ipv4|198.51.100.0|256||available
+`
+
+func TestParseDelegatedStats(t *testing.T) {
+	records, err := ParseDelegatedStats(strings.NewReader(delegatedSample))
+	require.NoError(t, err)
+
+	require.Len(t, records, 4)
+	assert.Equal(t, DelegatedRecord{
+		Registry: "ripencc", Country: "FR", Type: "ipv4", Date: "20100712", Status: "allocated",
+		Prefixes: mustPrefixes(t, "2.0.0.0/12"),
+	}, records[0])
+	assert.Equal(t, mustPrefixes(t, "192.0.2.0/23", "192.0.4.0/24"), records[1].Prefixes)
+	assert.Equal(t, "assigned", records[1].Status)
+	assert.Equal(t, mustPrefixes(t, "2001:db8::/32"), records[2].Prefixes)
+	assert.Equal(t, "available", records[3].Status)
+	assert.Empty(t, records[3].Country)
+}
+
+func TestParseDelegatedStats_Errors(t *testing.T) {
+	_, err := ParseDelegatedStats(strings.NewReader("2|x|1|1|1|1|0\nripencc|FR|ipv4|2.0.0|256|20100712|allocated\n"))
+	assert.EqualError(t, err, `line 2: invalid IPv4 start address "2.0.0"`)
+
+	_, err = ParseDelegatedStats(strings.NewReader("ripencc|FR|ipv4|255.255.255.0|512|20100712|allocated\n"))
+	assert.EqualError(t, err, `line 1: invalid IPv4 address count "512"`)
+
+	_, err = ParseDelegatedStats(strings.NewReader("ripencc|DE|ipv6|2001:db8::|129|20050101|allocated\n"))
+	assert.EqualError(t, err, `line 1: invalid IPv6 prefix length "129"`)
+
+	_, err = ParseDelegatedStats(strings.NewReader("2|x|1|1|1|1|0\nripencc|FR\n"))
+	assert.EqualError(t, err, "line 2: expected at least 7 fields, got 2")
+}
+
+func TestSummarizeDelegated(t *testing.T) {
+	records, err := ParseDelegatedStats(strings.NewReader(delegatedSample + "arin|NL|ipv4|192.0.5.0|256|20210101|allocated\n"))
+	require.NoError(t, err)
+
+	byCountry, err := SummarizeDelegated(records, GroupByCountry)
+	require.NoError(t, err)
+	require.Len(t, byCountry, 4)
+	assert.Equal(t, "", byCountry[0].Key)
+	assert.Equal(t, DelegatedSummary{
+		Key:           "NL",
+		Records:       2,
+		IPv4Addresses: 1024,
+		Prefixes:      mustPrefixes(t, "192.0.2.0/23", "192.0.4.0/23"),
+	}, byCountry[3])
+	assert.Equal(t, DelegatedSummary{Key: "DE", Records: 1, IPv6Prefixes: 1, Prefixes: mustPrefixes(t, "2001:db8::/32")}, byCountry[1])
+
+	byRegistry, err := SummarizeDelegated(records, GroupByRegistry)
+	require.NoError(t, err)
+	require.Len(t, byRegistry, 2)
+	assert.Equal(t, "arin", byRegistry[0].Key)
+	assert.Equal(t, 4, byRegistry[1].Records)
+	assert.Equal(t, uint64(1<<20+768+256), byRegistry[1].IPv4Addresses)
+
+	_, err = SummarizeDelegated(records, "asn")
+	assert.EqualError(t, err, `unknown grouping "asn"`)
+}
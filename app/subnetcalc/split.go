@@ -0,0 +1,84 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+)
+
+// SplitResult describes how a prefix divides into subnets of one length.
+//
+// Count is the number of subnets that fit, and Subnets lists them in order up
+// to the limit passed to SplitPrefix. Warnings reports IPv6 splits that are
+// not on a nibble boundary.
+type SplitResult struct {
+	Prefix   netip.Prefix
+	Bits     int
+	Count    *big.Int
+	Subnets  []netip.Prefix
+	Warnings []string
+}
+
+// SplitPrefix divides prefix into subnets of length bits using 128-bit math,
+// so IPv6 prefixes of any size can be split. At most limit subnets are
+// listed. Host bits of prefix are ignored.
+//
+// Reverse DNS for IPv6 is delegated on nibble (4-bit) boundaries, so a split
+// to a length that is not a multiple of 4 adds a warning: each subnet would
+// need several ip6.arpa zones.
+func SplitPrefix(prefix netip.Prefix, bits int, limit int) (SplitResult, error) {
+	if !prefix.IsValid() {
+		return SplitResult{}, errors.New("invalid prefix")
+	}
+	prefix = prefix.Masked()
+	if bits < prefix.Bits() || bits > prefix.Addr().BitLen() {
+		return SplitResult{}, fmt.Errorf("cannot split %s into /%d subnets", prefix, bits)
+	}
+
+	result := SplitResult{
+		Prefix: prefix,
+		Bits:   bits,
+		Count:  new(big.Int).Lsh(big.NewInt(1), uint(bits-prefix.Bits())),
+	}
+
+	step := new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-bits))
+	last := lastAddr(prefix)
+	for addr := prefix.Addr(); len(result.Subnets) < limit; addr = addAddr(addr, step) {
+		subnet := netip.PrefixFrom(addr, bits)
+		result.Subnets = append(result.Subnets, subnet)
+		if lastAddr(subnet) == last {
+			break
+		}
+	}
+
+	if prefix.Addr().Is6() && bits%4 != 0 {
+		nibble := (bits + 3) / 4 * 4
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"/%d is not on a nibble boundary; reverse DNS for each subnet needs %d /%d ip6.arpa zones",
+			bits, 1<<(nibble-bits), nibble))
+	}
+	return result, nil
+}
+
+// NibbleSplits returns, for every nibble boundary from the next one after
+// prefix down to /64, how many subnets of that length fit in an IPv6
+// prefix. No subnets are listed. Prefixes of /64 or longer have nothing
+// left to plan and are rejected.
+func NibbleSplits(prefix netip.Prefix) ([]SplitResult, error) {
+	if !prefix.IsValid() || !prefix.Addr().Is6() {
+		return nil, errors.New("nibble planning requires an IPv6 prefix")
+	}
+	if prefix.Bits() >= 64 {
+		return nil, fmt.Errorf("%s is already at /64 or longer, nothing to plan", prefix)
+	}
+	var splits []SplitResult
+	for bits := prefix.Bits()/4*4 + 4; bits <= 64; bits += 4 {
+		split, err := SplitPrefix(prefix, bits, 0)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, split)
+	}
+	return splits, nil
+}
@@ -0,0 +1,78 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSplitPrefix() {
+	split, _ := SplitPrefix(netip.MustParsePrefix("2001:db8:abcd::/48"), 56, 3)
+	fmt.Println(split.Count, split.Subnets)
+	// Output: 256 [2001:db8:abcd::/56 2001:db8:abcd:100::/56 2001:db8:abcd:200::/56]
+}
+
+func TestSplitPrefix_IPv4(t *testing.T) {
+	split, err := SplitPrefix(netip.MustParsePrefix("10.0.0.77/24"), 26, 10)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/24"), split.Prefix)
+	assert.Equal(t, big.NewInt(4), split.Count)
+	assert.Equal(t, mustPrefixes(t, "10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/26", "10.0.0.192/26"), split.Subnets)
+	assert.Empty(t, split.Warnings)
+}
+
+func TestSplitPrefix_IPv6(t *testing.T) {
+	split, err := SplitPrefix(netip.MustParsePrefix("2001:db8::/32"), 64, 2)
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 32), split.Count)
+	assert.Equal(t, mustPrefixes(t, "2001:db8::/64", "2001:db8:0:1::/64"), split.Subnets)
+
+	split, err = SplitPrefix(netip.MustParsePrefix("::/0"), 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, mustPrefixes(t, "::/1", "8000::/1"), split.Subnets)
+}
+
+func TestSplitPrefix_NibbleWarning(t *testing.T) {
+	split, err := SplitPrefix(netip.MustParsePrefix("2001:db8::/48"), 58, 0)
+	require.NoError(t, err)
+	assert.Empty(t, split.Subnets)
+	assert.Equal(t, big.NewInt(1024), split.Count)
+	assert.Equal(t, []string{"/58 is not on a nibble boundary; reverse DNS for each subnet needs 4 /60 ip6.arpa zones"}, split.Warnings)
+}
+
+func TestSplitPrefix_Errors(t *testing.T) {
+	_, err := SplitPrefix(netip.MustParsePrefix("10.0.0.0/24"), 23, 1)
+	assert.EqualError(t, err, "cannot split 10.0.0.0/24 into /23 subnets")
+
+	_, err = SplitPrefix(netip.MustParsePrefix("2001:db8::/64"), 129, 1)
+	assert.EqualError(t, err, "cannot split 2001:db8::/64 into /129 subnets")
+
+	_, err = SplitPrefix(netip.Prefix{}, 24, 1)
+	assert.EqualError(t, err, "invalid prefix")
+}
+
+func TestNibbleSplits(t *testing.T) {
+	splits, err := NibbleSplits(netip.MustParsePrefix("2001:db8:abcd::/46"))
+	require.NoError(t, err)
+
+	var got []string
+	for _, s := range splits {
+		got = append(got, fmt.Sprintf("/%d=%s", s.Bits, s.Count))
+	}
+	assert.Equal(t, []string{"/48=4", "/52=64", "/56=1024", "/60=16384", "/64=262144"}, got)
+
+	splits, err = NibbleSplits(netip.MustParsePrefix("2001:db8::/62"))
+	require.NoError(t, err)
+	require.Len(t, splits, 1)
+	assert.Equal(t, 64, splits[0].Bits)
+
+	_, err = NibbleSplits(netip.MustParsePrefix("2001:db8::/64"))
+	assert.EqualError(t, err, "2001:db8::/64 is already at /64 or longer, nothing to plan")
+
+	_, err = NibbleSplits(netip.MustParsePrefix("10.0.0.0/8"))
+	assert.Error(t, err)
+}
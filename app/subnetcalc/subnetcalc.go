@@ -1,21 +1,47 @@
-// Package subnetcalc calculates IPv4 subnet information from CIDR notation.
+// Package subnetcalc calculates subnet information from CIDR notation.
 // It computes network addresses, broadcast IPs, subnet masks, and address counts
-// for any valid IPv4 prefix.
+// for any valid IPv4 prefix, and usable ranges and notations of IPv6 prefixes
+// through a Calculator with WithIPv6Policy(IPv6Allow).
 package subnetcalc
 
 import (
 	"encoding/binary"
-	"errors"
+	"fmt"
 	"math"
 	"net/netip"
 )
 
-// SubnetInfo represents calculated information about an IPv4 subnet.
+// SubnetInfo represents calculated information about an IPv4 subnet, as
+// returned by the IPv4-only CalcSubnetInfo functions. Result, returned by
+// Calculator, covers IPv6 as well.
+//
+// Reserved is only set when a cloud provider is selected; see
+// CalcProviderSubnetInfo. It encodes to JSON and YAML with snake_case keys
+// that match those of Result.
 type SubnetInfo struct {
-	NetworkAddress netip.Addr
-	BroadcastIP    netip.Addr
-	SubnetMask     netip.Addr
-	TotalIP        uint
+	NetworkAddress netip.Addr        `json:"network_address" yaml:"network_address"`
+	BroadcastIP    netip.Addr        `json:"broadcast_address" yaml:"broadcast_address"`
+	SubnetMask     netip.Addr        `json:"subnet_mask" yaml:"subnet_mask"`
+	TotalIP        uint              `json:"total_ips" yaml:"total_ips"`
+	Reserved       []ReservedAddress `json:"reserved,omitempty" yaml:"reserved,omitempty"`
+}
+
+// UsableHosts returns the number of addresses that can be assigned to hosts.
+//
+// Without provider reservations the network and broadcast addresses are
+// excluded, except for /31 (RFC 3021) and /32 prefixes where every address
+// is usable.
+func (s SubnetInfo) UsableHosts() uint {
+	if s.Reserved != nil {
+		if uint(len(s.Reserved)) >= s.TotalIP {
+			return 0
+		}
+		return s.TotalIP - uint(len(s.Reserved))
+	}
+	if s.TotalIP <= 2 {
+		return s.TotalIP
+	}
+	return s.TotalIP - 2
 }
 
 type masks struct {
@@ -63,12 +89,44 @@ func uint32ToAddr(ipInt uint32) netip.Addr {
 	return netip.AddrFrom4(subnetMaskBytesArray)
 }
 
+// HostBitsError reports a prefix written with host bits set, such as
+// 172.16.38.94/27, and the canonical prefix it normalizes to.
+type HostBitsError struct {
+	Prefix    netip.Prefix
+	Canonical netip.Prefix
+}
+
+func (e *HostBitsError) Error() string {
+	return fmt.Sprintf("%s has host bits set; the canonical prefix is %s", e.Prefix, e.Canonical)
+}
+
+// CheckHostBits returns a *HostBitsError if prefix has host bits set.
+func CheckHostBits(prefix netip.Prefix) error {
+	if canonical := prefix.Masked(); prefix.IsValid() && prefix != canonical {
+		return &HostBitsError{Prefix: prefix, Canonical: canonical}
+	}
+	return nil
+}
+
+// CalcSubnetInfoStrict is like CalcSubnetInfo but rejects prefixes with host
+// bits set with a *HostBitsError instead of masking them, since in
+// automation they usually mean a typo.
+func CalcSubnetInfoStrict(prefix netip.Prefix) (SubnetInfo, error) {
+	result, err := NewCalculator(WithStrict(true)).Calculate(prefix)
+	if err != nil {
+		return SubnetInfo{}, err
+	}
+	return result.SubnetInfo(), nil
+}
+
 // CalcSubnetInfo calculates subnet information for the given IPv4 prefix.
 // It returns the network address, broadcast IP, subnet mask, and total IP count.
 //
 // Special cases:
 //   - /32 prefix: single host, NetworkAddress equals BroadcastIP
-//   - IPv6 prefix: returns error (not yet supported)
+//   - Host bits set: silently masked; see CalcSubnetInfoStrict
+//   - IPv6 prefix: returns the error "IPv6 not supported yet"; use a
+//     Calculator with WithIPv6Policy(IPv6Allow) instead
 //   - Invalid prefix: returns error
 //
 // Example:
@@ -79,17 +137,22 @@ func uint32ToAddr(ipInt uint32) netip.Addr {
 //	    return err
 //	}
 //	fmt.Printf("Network: %s\n", info.NetworkAddress)
+//
+// CalcSubnetInfo is the IPv4-only wrapper kept for compatibility. New code
+// should use Calculator, which handles IPv6, providers and strict mode and
+// returns a Result.
 func CalcSubnetInfo(prefix netip.Prefix) (SubnetInfo, error) {
-	if !prefix.IsValid() {
-		return SubnetInfo{}, errors.New("invalid prefix")
-	}
-
-	if prefix.Addr().Is6() {
-		return SubnetInfo{}, errors.New("IPv6 not supported yet")
+	result, err := NewCalculator().Calculate(prefix)
+	if err != nil {
+		return SubnetInfo{}, err
 	}
+	return result.SubnetInfo(), nil
+}
 
+// calcIPv4SubnetInfo calculates subnet information for a valid IPv4 prefix.
+func calcIPv4SubnetInfo(prefix netip.Prefix) SubnetInfo {
 	if prefix.IsSingleIP() {
-		return getSingleIPSubnetInfo(prefix.Addr()), nil
+		return getSingleIPSubnetInfo(prefix.Addr())
 	}
 
 	masks := calcMasks(prefix)
@@ -103,5 +166,5 @@ func CalcSubnetInfo(prefix netip.Prefix) (SubnetInfo, error) {
 		BroadcastIP:    broadcastIP,
 		SubnetMask:     subnetMask,
 		TotalIP:        totalIP,
-	}, nil
+	}
 }
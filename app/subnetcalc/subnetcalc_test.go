@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func ExampleCalcSubnetInfo() {
@@ -1668,3 +1669,27 @@ func TestCalcSubnetInfo_AnyAddressInSubnet(t *testing.T) {
 		})
 	}
 }
+
+func TestCalcSubnetInfoStrict(t *testing.T) {
+	_, err := CalcSubnetInfoStrict(netip.MustParsePrefix("172.16.38.94/27"))
+	var hostBits *HostBitsError
+	require.ErrorAs(t, err, &hostBits)
+	assert.Equal(t, netip.MustParsePrefix("172.16.38.64/27"), hostBits.Canonical)
+	assert.EqualError(t, err, "172.16.38.94/27 has host bits set; the canonical prefix is 172.16.38.64/27")
+
+	info, err := CalcSubnetInfoStrict(netip.MustParsePrefix("172.16.38.64/27"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("172.16.38.64"), info.NetworkAddress)
+
+	_, err = CalcSubnetInfoStrict(netip.MustParsePrefix("2001:db8::1/64"))
+	assert.EqualError(t, err, "IPv6 not supported yet")
+
+	_, err = CalcSubnetInfoStrict(netip.Prefix{})
+	assert.EqualError(t, err, "invalid prefix")
+}
+
+func TestCheckHostBits(t *testing.T) {
+	assert.NoError(t, CheckHostBits(netip.MustParsePrefix("2001:db8::/32")))
+	assert.NoError(t, CheckHostBits(netip.MustParsePrefix("192.0.2.1/32")))
+	assert.Error(t, CheckHostBits(netip.MustParsePrefix("2001:db8::1/64")))
+}
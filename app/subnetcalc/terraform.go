@@ -0,0 +1,114 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+)
+
+// CIDRSubnet mirrors Terraform's cidrsubnet(prefix, newbits, netnum): it
+// extends the prefix length by newbits and returns the netnum-th subnet of
+// that size. Host bits of prefix are ignored.
+//
+// Example:
+//
+//	CIDRSubnet(netip.MustParsePrefix("172.16.0.0/12"), 4, 2) // 172.18.0.0/16
+func CIDRSubnet(prefix netip.Prefix, newbits int, netnum int64) (netip.Prefix, error) {
+	if !prefix.IsValid() {
+		return netip.Prefix{}, errors.New("invalid prefix")
+	}
+	prefix = prefix.Masked()
+	newLen := prefix.Bits() + newbits
+	if newbits < 0 || newLen > prefix.Addr().BitLen() {
+		return netip.Prefix{}, fmt.Errorf("insufficient address space to extend prefix of %d by %d", prefix.Bits(), newbits)
+	}
+	num := big.NewInt(netnum)
+	if netnum < 0 || num.BitLen() > newbits {
+		return netip.Prefix{}, fmt.Errorf("prefix extension of %d does not accommodate a subnet numbered %d", newbits, netnum)
+	}
+
+	offset := num.Lsh(num, uint(prefix.Addr().BitLen()-newLen))
+	return netip.PrefixFrom(addAddr(prefix.Addr(), offset), newLen), nil
+}
+
+// CIDRSubnets mirrors Terraform's cidrsubnets(prefix, newbits...): it
+// allocates consecutive subnets, each newbits longer than prefix, packing
+// them in order and skipping only what alignment requires.
+//
+// Example:
+//
+//	CIDRSubnets(netip.MustParsePrefix("10.1.0.0/16"), 4, 4, 8, 4)
+//	// [10.1.0.0/20 10.1.16.0/20 10.1.32.0/24 10.1.48.0/20]
+func CIDRSubnets(prefix netip.Prefix, newbits ...int) ([]netip.Prefix, error) {
+	if len(newbits) == 0 {
+		return nil, nil
+	}
+	current, err := CIDRSubnet(prefix, newbits[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	prefix = prefix.Masked()
+	subnets := []netip.Prefix{current}
+
+	for _, bits := range newbits[1:] {
+		newLen := prefix.Bits() + bits
+		if bits < 0 || newLen > prefix.Addr().BitLen() {
+			return nil, fmt.Errorf("insufficient address space to extend prefix of %d by %d", prefix.Bits(), bits)
+		}
+		aligned := netip.PrefixFrom(lastAddr(current), newLen).Masked()
+		next := lastAddr(aligned).Next()
+		if !next.IsValid() || !prefix.Contains(next) {
+			return nil, fmt.Errorf("not enough remaining address space for a subnet with a prefix of %d bits after %s", newLen, current)
+		}
+		current = netip.PrefixFrom(next, newLen)
+		subnets = append(subnets, current)
+	}
+	return subnets, nil
+}
+
+// CIDRHost mirrors Terraform's cidrhost(prefix, hostnum): it returns the
+// hostnum-th address of prefix. Negative host numbers count back from the
+// end, so -1 is the last address.
+//
+// Example:
+//
+//	CIDRHost(netip.MustParsePrefix("10.12.112.0/20"), 268) // 10.12.113.12
+func CIDRHost(prefix netip.Prefix, hostnum int64) (netip.Addr, error) {
+	if !prefix.IsValid() {
+		return netip.Addr{}, errors.New("invalid prefix")
+	}
+	prefix = prefix.Masked()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+	num := big.NewInt(hostnum)
+	if hostnum < 0 {
+		num.Add(num, size)
+	}
+	if num.Sign() < 0 || num.Cmp(size) >= 0 {
+		return netip.Addr{}, fmt.Errorf("prefix of %d does not accommodate a host numbered %d", prefix.Bits(), hostnum)
+	}
+	return addAddr(prefix.Addr(), num), nil
+}
+
+// CIDRNetmask mirrors Terraform's cidrnetmask(prefix): it returns the
+// dotted-decimal subnet mask of an IPv4 prefix.
+func CIDRNetmask(prefix netip.Prefix) (netip.Addr, error) {
+	if !prefix.IsValid() {
+		return netip.Addr{}, errors.New("invalid prefix")
+	}
+	if prefix.Addr().Is6() {
+		return netip.Addr{}, errors.New("IPv6 addresses cannot have a netmask")
+	}
+	return uint32ToAddr(calcMasks(prefix).SubnetMask), nil
+}
+
+// addAddr returns addr+offset. The caller guarantees the sum fits in the
+// address family.
+func addAddr(addr netip.Addr, offset *big.Int) netip.Addr {
+	b := addr.AsSlice()
+	sum := new(big.Int).SetBytes(b)
+	sum.Add(sum, offset)
+	sum.FillBytes(b)
+	result, _ := netip.AddrFromSlice(b)
+	return result
+}
@@ -0,0 +1,142 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleCIDRSubnet() {
+	subnet, _ := CIDRSubnet(netip.MustParsePrefix("10.0.0.0/16"), 8, 2)
+	fmt.Println(subnet)
+	// Output: 10.0.2.0/24
+}
+
+// The expected values below are the examples from Terraform's function
+// documentation.
+
+func TestCIDRSubnet(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		newbits int
+		netnum  int64
+		want    string
+	}{
+		{"172.16.0.0/12", 4, 2, "172.18.0.0/16"},
+		{"10.1.2.0/24", 4, 15, "10.1.2.240/28"},
+		{"fd00:fd12:3456:7890::/56", 16, 162, "fd00:fd12:3456:7800:a200::/72"},
+		{"10.0.0.0/16", 8, 2, "10.0.2.0/24"},
+		{"10.0.0.0/16", 0, 0, "10.0.0.0/16"},
+		{"10.1.2.3/24", 8, 255, "10.1.2.255/32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d %d", tt.prefix, tt.newbits, tt.netnum), func(t *testing.T) {
+			got, err := CIDRSubnet(netip.MustParsePrefix(tt.prefix), tt.newbits, tt.netnum)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestCIDRSubnet_Errors(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		newbits int
+		netnum  int64
+		wantErr string
+	}{
+		{"10.0.0.0/24", 9, 0, "insufficient address space to extend prefix of 24 by 9"},
+		{"10.0.0.0/24", -1, 0, "insufficient address space to extend prefix of 24 by -1"},
+		{"10.0.0.0/16", 4, 16, "prefix extension of 4 does not accommodate a subnet numbered 16"},
+		{"10.0.0.0/16", 4, -1, "prefix extension of 4 does not accommodate a subnet numbered -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantErr, func(t *testing.T) {
+			_, err := CIDRSubnet(netip.MustParsePrefix(tt.prefix), tt.newbits, tt.netnum)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := CIDRSubnet(netip.Prefix{}, 1, 0)
+	assert.EqualError(t, err, "invalid prefix")
+}
+
+func TestCIDRSubnets(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		newbits []int
+		want    []string
+	}{
+		{"10.1.0.0/16", []int{4, 4, 8, 4}, []string{"10.1.0.0/20", "10.1.16.0/20", "10.1.32.0/24", "10.1.48.0/20"}},
+		{
+			"fd00:fd12:3456:7890::/56",
+			[]int{16, 16, 16, 32},
+			[]string{"fd00:fd12:3456:7800::/72", "fd00:fd12:3456:7800:100::/72", "fd00:fd12:3456:7800:200::/72", "fd00:fd12:3456:7800:300::/88"},
+		},
+		{"10.0.0.0/24", []int{1, 1}, []string{"10.0.0.0/25", "10.0.0.128/25"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, err := CIDRSubnets(netip.MustParsePrefix(tt.prefix), tt.newbits...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, prefixStrings(got))
+		})
+	}
+}
+
+func TestCIDRSubnets_Exhausted(t *testing.T) {
+	_, err := CIDRSubnets(netip.MustParsePrefix("10.0.0.0/24"), 1, 1, 2)
+	assert.EqualError(t, err, "not enough remaining address space for a subnet with a prefix of 26 bits after 10.0.0.128/25")
+
+	_, err = CIDRSubnets(netip.MustParsePrefix("255.255.255.0/24"), 1, 1)
+	require.NoError(t, err)
+	_, err = CIDRSubnets(netip.MustParsePrefix("255.255.255.0/24"), 1, 1, 1)
+	assert.EqualError(t, err, "not enough remaining address space for a subnet with a prefix of 25 bits after 255.255.255.128/25")
+}
+
+func TestCIDRHost(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		hostnum int64
+		want    string
+	}{
+		{"10.12.112.0/20", 16, "10.12.112.16"},
+		{"10.12.112.0/20", 268, "10.12.113.12"},
+		{"fd00:fd12:3456:7890:00a2::/72", 34, "fd00:fd12:3456:7890::22"},
+		{"10.0.0.0/24", -1, "10.0.0.255"},
+		{"10.0.0.0/24", -2, "10.0.0.254"},
+		{"10.0.0.0/24", -256, "10.0.0.0"},
+		{"2001:db8::/64", -1, "2001:db8::ffff:ffff:ffff:ffff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.prefix, tt.hostnum), func(t *testing.T) {
+			got, err := CIDRHost(netip.MustParsePrefix(tt.prefix), tt.hostnum)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestCIDRHost_OutOfRange(t *testing.T) {
+	_, err := CIDRHost(netip.MustParsePrefix("10.0.0.0/24"), 256)
+	assert.EqualError(t, err, "prefix of 24 does not accommodate a host numbered 256")
+
+	_, err = CIDRHost(netip.MustParsePrefix("10.0.0.0/24"), -257)
+	assert.EqualError(t, err, "prefix of 24 does not accommodate a host numbered -257")
+}
+
+func TestCIDRNetmask(t *testing.T) {
+	got, err := CIDRNetmask(netip.MustParsePrefix("172.16.0.0/12"))
+	require.NoError(t, err)
+	assert.Equal(t, "255.240.0.0", got.String())
+
+	_, err = CIDRNetmask(netip.MustParsePrefix("2001:db8::/32"))
+	assert.EqualError(t, err, "IPv6 addresses cannot have a netmask")
+}
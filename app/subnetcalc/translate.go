@@ -0,0 +1,121 @@
+package subnetcalc
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+// WellKnownNAT64Prefix is the RFC 6052 well-known prefix used by DNS64 and
+// NAT64 when no network-specific prefix is configured.
+var WellKnownNAT64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// sixToFourPrefix is the 6to4 prefix of RFC 3056.
+var sixToFourPrefix = netip.MustParsePrefix("2002::/16")
+
+// nat64PrefixLengths are the prefix lengths allowed by RFC 6052.
+var nat64PrefixLengths = []int{32, 40, 48, 56, 64, 96}
+
+// nat64Positions returns the byte offsets an IPv4 address occupies in an
+// IPv6 address built from a NAT64 prefix. Bits 64 to 71 (the "u" octet) are
+// always skipped.
+func nat64Positions(prefix netip.Prefix) ([4]int, error) {
+	var pos [4]int
+	if !prefix.IsValid() || !prefix.Addr().Is6() {
+		return pos, errors.New("NAT64 prefix must be an IPv6 prefix")
+	}
+	valid := false
+	for _, bits := range nat64PrefixLengths {
+		valid = valid || prefix.Bits() == bits
+	}
+	if !valid {
+		return pos, fmt.Errorf("NAT64 prefix length must be one of %v, got /%d", nat64PrefixLengths, prefix.Bits())
+	}
+
+	i := prefix.Bits() / 8
+	for n := range pos {
+		if i == 8 {
+			i++
+		}
+		pos[n] = i
+		i++
+	}
+	return pos, nil
+}
+
+// EmbedIPv4 returns the IPv4-embedded IPv6 address of addr under a NAT64
+// prefix, as specified in RFC 6052 section 2.2.
+func EmbedIPv4(prefix netip.Prefix, addr netip.Addr) (netip.Addr, error) {
+	pos, err := nat64Positions(prefix)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if !addr.Is4() {
+		return netip.Addr{}, fmt.Errorf("%s is not an IPv4 address", addr)
+	}
+
+	b := prefix.Masked().Addr().As16()
+	v4 := addr.As4()
+	for n, i := range pos {
+		b[i] = v4[n]
+	}
+	return netip.AddrFrom16(b), nil
+}
+
+// ExtractIPv4 returns the IPv4 address embedded in addr under a NAT64 prefix.
+// It is the inverse of EmbedIPv4.
+func ExtractIPv4(prefix netip.Prefix, addr netip.Addr) (netip.Addr, error) {
+	pos, err := nat64Positions(prefix)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	addr = addr.WithZone("")
+	if !prefix.Masked().Contains(addr) {
+		return netip.Addr{}, fmt.Errorf("%s is not inside NAT64 prefix %s", addr, prefix.Masked())
+	}
+
+	b := addr.As16()
+	var v4 [4]byte
+	for n, i := range pos {
+		v4[n] = b[i]
+	}
+	return netip.AddrFrom4(v4), nil
+}
+
+// MapIPv4 returns the IPv4-mapped IPv6 address ::ffff:a.b.c.d of addr.
+func MapIPv4(addr netip.Addr) (netip.Addr, error) {
+	if !addr.Is4() {
+		return netip.Addr{}, fmt.Errorf("%s is not an IPv4 address", addr)
+	}
+	return netip.AddrFrom16(addr.As16()), nil
+}
+
+// UnmapIPv4 returns the IPv4 address of an IPv4-mapped IPv6 address.
+func UnmapIPv4(addr netip.Addr) (netip.Addr, error) {
+	if !addr.Is4In6() {
+		return netip.Addr{}, fmt.Errorf("%s is not an IPv4-mapped address", addr)
+	}
+	return addr.Unmap(), nil
+}
+
+// SixToFourPrefix returns the 2002::/16 based /48 that 6to4 derives from
+// an IPv4 address (RFC 3056).
+func SixToFourPrefix(addr netip.Addr) (netip.Prefix, error) {
+	if !addr.Is4() {
+		return netip.Prefix{}, fmt.Errorf("%s is not an IPv4 address", addr)
+	}
+	b := sixToFourPrefix.Addr().As16()
+	v4 := addr.As4()
+	copy(b[2:6], v4[:])
+	return netip.PrefixFrom(netip.AddrFrom16(b), 48), nil
+}
+
+// SixToFourIPv4 returns the IPv4 address embedded in a 6to4 address.
+func SixToFourIPv4(addr netip.Addr) (netip.Addr, error) {
+	addr = addr.WithZone("")
+	if !sixToFourPrefix.Contains(addr) {
+		return netip.Addr{}, fmt.Errorf("%s is not a 6to4 address", addr)
+	}
+	b := addr.As16()
+	return netip.AddrFrom4([4]byte{b[2], b[3], b[4], b[5]}), nil
+}
@@ -0,0 +1,83 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmbedIPv4_RFC6052 checks the examples of RFC 6052 section 2.4.
+func TestEmbedIPv4_RFC6052(t *testing.T) {
+	v4 := netip.MustParseAddr("192.0.2.33")
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"2001:db8::/32", "2001:db8:c000:221::"},
+		{"2001:db8:100::/40", "2001:db8:1c0:2:21::"},
+		{"2001:db8:122::/48", "2001:db8:122:c000:2:2100::"},
+		{"2001:db8:122:300::/56", "2001:db8:122:3c0:0:221::"},
+		{"2001:db8:122:344::/64", "2001:db8:122:344:c0:2:2100:0"},
+		{"2001:db8:122:344::/96", "2001:db8:122:344::192.0.2.33"},
+		{"64:ff9b::/96", "64:ff9b::192.0.2.33"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			prefix := netip.MustParsePrefix(tt.prefix)
+			addr, err := EmbedIPv4(prefix, v4)
+			require.NoError(t, err)
+			assert.Equal(t, netip.MustParseAddr(tt.want), addr)
+
+			back, err := ExtractIPv4(prefix, addr)
+			require.NoError(t, err)
+			assert.Equal(t, v4, back)
+		})
+	}
+}
+
+func TestEmbedIPv4_Errors(t *testing.T) {
+	v4 := netip.MustParseAddr("192.0.2.33")
+
+	_, err := EmbedIPv4(netip.MustParsePrefix("2001:db8::/60"), v4)
+	assert.EqualError(t, err, "NAT64 prefix length must be one of [32 40 48 56 64 96], got /60")
+
+	_, err = EmbedIPv4(netip.MustParsePrefix("10.0.0.0/8"), v4)
+	assert.EqualError(t, err, "NAT64 prefix must be an IPv6 prefix")
+
+	_, err = EmbedIPv4(WellKnownNAT64Prefix, netip.MustParseAddr("2001:db8::1"))
+	assert.EqualError(t, err, "2001:db8::1 is not an IPv4 address")
+
+	_, err = ExtractIPv4(WellKnownNAT64Prefix, netip.MustParseAddr("2001:db8::1"))
+	assert.EqualError(t, err, "2001:db8::1 is not inside NAT64 prefix 64:ff9b::/96")
+}
+
+func TestMapIPv4(t *testing.T) {
+	mapped, err := MapIPv4(netip.MustParseAddr("198.51.100.7"))
+	require.NoError(t, err)
+	assert.Equal(t, "::ffff:198.51.100.7", mapped.String())
+
+	v4, err := UnmapIPv4(mapped)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("198.51.100.7"), v4)
+
+	_, err = UnmapIPv4(netip.MustParseAddr("64:ff9b::c633:6407"))
+	assert.EqualError(t, err, "64:ff9b::c633:6407 is not an IPv4-mapped address")
+
+	_, err = MapIPv4(mapped)
+	assert.Error(t, err)
+}
+
+func TestSixToFour(t *testing.T) {
+	prefix, err := SixToFourPrefix(netip.MustParseAddr("192.88.99.1"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("2002:c058:6301::/48"), prefix)
+
+	v4, err := SixToFourIPv4(netip.MustParseAddr("2002:c058:6301:1::1"))
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("192.88.99.1"), v4)
+
+	_, err = SixToFourIPv4(netip.MustParseAddr("2001:db8::1"))
+	assert.EqualError(t, err, "2001:db8::1 is not a 6to4 address")
+}
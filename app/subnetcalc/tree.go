@@ -0,0 +1,206 @@
+package subnetcalc
+
+import (
+	"fmt"
+	"html"
+	"net/netip"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// TreeNode is an entry of an address tree built by BuildTree. Free nodes are
+// the unallocated gaps inside a parent and have no label.
+//
+// Utilization is the share of the prefix covered by its children. Allocated
+// leaves count as fully used and free gaps as unused.
+type TreeNode struct {
+	Label       string       `json:"label,omitempty" yaml:"label,omitempty"`
+	Prefix      netip.Prefix `json:"prefix" yaml:"prefix"`
+	Free        bool         `json:"free" yaml:"free"`
+	Utilization float64      `json:"utilization" yaml:"utilization"`
+	Children    []TreeNode   `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// TreeFormat selects the output produced by RenderTree.
+type TreeFormat string
+
+// Supported tree formats.
+const (
+	TreeText TreeFormat = "text"
+	TreeHTML TreeFormat = "html"
+	TreeSVG  TreeFormat = "svg"
+)
+
+// TreeFormats lists every format RenderTree understands.
+var TreeFormats = []TreeFormat{TreeText, TreeHTML, TreeSVG}
+
+// treeBarWidth is the number of characters in a text utilization bar.
+const treeBarWidth = 20
+
+// BuildTree nests prefixes under the smallest prefix containing them and
+// fills the space each parent does not allocate with free nodes. Host bits
+// are masked off, and identical prefixes nest under the first occurrence.
+// Top-level entries have no parent, so no gaps are reported between them.
+func BuildTree(prefixes []LabeledPrefix) []TreeNode {
+	sorted := make([]LabeledPrefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		if p.Prefix.IsValid() {
+			sorted = append(sorted, LabeledPrefix{Label: p.Label, Prefix: p.Prefix.Masked()})
+		}
+	}
+	slices.SortStableFunc(sorted, func(a, b LabeledPrefix) int {
+		if c := a.Prefix.Addr().Compare(b.Prefix.Addr()); c != 0 {
+			return c
+		}
+		return a.Prefix.Bits() - b.Prefix.Bits()
+	})
+
+	var roots []TreeNode
+	var stack []*TreeNode
+	for _, p := range sorted {
+		for len(stack) > 0 && !stack[len(stack)-1].Prefix.Contains(p.Prefix.Addr()) {
+			stack = stack[:len(stack)-1]
+		}
+		node := TreeNode{Label: p.Label, Prefix: p.Prefix}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+			stack = append(stack, &roots[len(roots)-1])
+			continue
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, &parent.Children[len(parent.Children)-1])
+	}
+
+	for i := range roots {
+		fillTree(&roots[i])
+	}
+	return roots
+}
+
+// fillTree adds the free gaps between the children of n and computes the
+// utilization of n and its descendants.
+func fillTree(n *TreeNode) {
+	if len(n.Children) == 0 {
+		n.Utilization = 1
+		return
+	}
+
+	children := make([]netip.Prefix, 0, len(n.Children))
+	for i := range n.Children {
+		fillTree(&n.Children[i])
+		children = append(children, n.Children[i].Prefix)
+	}
+	whole := NewPrefixSet(n.Prefix)
+	used := NewPrefixSet(children...)
+	n.Utilization = addressCount(used) / addressCount(whole)
+
+	for _, gap := range whole.Subtract(used).Prefixes() {
+		n.Children = append(n.Children, TreeNode{Prefix: gap, Free: true})
+	}
+	slices.SortStableFunc(n.Children, func(a, b TreeNode) int {
+		return a.Prefix.Addr().Compare(b.Prefix.Addr())
+	})
+}
+
+// RenderTree renders a tree built by BuildTree as indented text with
+// utilization bars, or as a standalone SVG image or HTML page.
+func RenderTree(nodes []TreeNode, format TreeFormat) (string, error) {
+	switch format {
+	case TreeText:
+		return renderTreeText(nodes), nil
+	case TreeHTML:
+		return renderTreeHTML(nodes), nil
+	case TreeSVG:
+		return renderTreeSVG(nodes), nil
+	default:
+		return "", fmt.Errorf("unknown tree format %q", format)
+	}
+}
+
+// treeRow is a node flattened for rendering, with its depth and the text
+// drawn in front of it in text output.
+type treeRow struct {
+	node   TreeNode
+	depth  int
+	branch string
+}
+
+func flattenTree(nodes []TreeNode) []treeRow {
+	var rows []treeRow
+	var walk func(nodes []TreeNode, depth int, indent string)
+	walk = func(nodes []TreeNode, depth int, indent string) {
+		for i, n := range nodes {
+			last := i == len(nodes)-1
+			branch, next := "├── ", "│   "
+			if last {
+				branch, next = "└── ", "    "
+			}
+			if depth == 0 {
+				branch, next = "", ""
+			}
+			rows = append(rows, treeRow{node: n, depth: depth, branch: indent + branch})
+			walk(n.Children, depth+1, indent+next)
+		}
+	}
+	walk(nodes, 0, "")
+	return rows
+}
+
+func treeLabel(n TreeNode) string {
+	if n.Free {
+		return "(free)"
+	}
+	return n.Label
+}
+
+func renderTreeText(nodes []TreeNode) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for _, row := range flattenTree(nodes) {
+		filled := int(row.node.Utilization*treeBarWidth + 0.5)
+		bar := strings.Repeat("#", filled) + strings.Repeat(".", treeBarWidth-filled)
+		fmt.Fprintf(w, "%s%s\t%s\t[%s]\t%5.1f%%\n", row.branch, row.node.Prefix, treeLabel(row.node), bar, row.node.Utilization*100)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// Layout of the SVG rendering, in pixels.
+const (
+	svgRowHeight = 22
+	svgIndent    = 16
+	svgBarX      = 420
+	svgBarWidth  = 200
+	svgWidth     = svgBarX + svgBarWidth + 70
+)
+
+func renderTreeSVG(nodes []TreeNode) string {
+	rows := flattenTree(nodes)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="13">`+"\n",
+		svgWidth, len(rows)*svgRowHeight+8)
+	for i, row := range rows {
+		y := i*svgRowHeight + 4
+		color := "#000"
+		if row.node.Free {
+			color = "#888"
+		}
+		fmt.Fprintf(&sb, `  <text x="%d" y="%d" fill="%s">%s <tspan font-weight="bold">%s</tspan></text>`+"\n",
+			8+row.depth*svgIndent, y+15, color, row.node.Prefix, html.EscapeString(treeLabel(row.node)))
+		fmt.Fprintf(&sb, `  <rect x="%d" y="%d" width="%d" height="14" fill="#eee" stroke="#999"/>`+"\n", svgBarX, y+3, svgBarWidth)
+		fmt.Fprintf(&sb, `  <rect x="%d" y="%d" width="%.1f" height="14" fill="#4a90d9"/>`+"\n", svgBarX, y+3, row.node.Utilization*svgBarWidth)
+		fmt.Fprintf(&sb, `  <text x="%d" y="%d">%.1f%%</text>`+"\n", svgBarX+svgBarWidth+8, y+15, row.node.Utilization*100)
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func renderTreeHTML(nodes []TreeNode) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Address map</title>\n</head>\n<body>\n")
+	sb.WriteString(renderTreeSVG(nodes))
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
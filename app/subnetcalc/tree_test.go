@@ -0,0 +1,80 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTree(t *testing.T) {
+	tree := BuildTree([]LabeledPrefix{
+		labeled("db", "10.0.1.0/24"),
+		labeled("site", "10.0.0.0/22"),
+		labeled("web", "10.0.0.7/24"),
+		labeled("lab", "192.168.0.0/24"),
+	})
+
+	require.Len(t, tree, 2)
+	site := tree[0]
+	assert.Equal(t, "site", site.Label)
+	assert.Equal(t, 0.5, site.Utilization)
+	assert.Equal(t, []TreeNode{
+		{Label: "web", Prefix: netip.MustParsePrefix("10.0.0.0/24"), Utilization: 1},
+		{Label: "db", Prefix: netip.MustParsePrefix("10.0.1.0/24"), Utilization: 1},
+		{Prefix: netip.MustParsePrefix("10.0.2.0/23"), Free: true},
+	}, site.Children)
+	assert.Equal(t, TreeNode{Label: "lab", Prefix: netip.MustParsePrefix("192.168.0.0/24"), Utilization: 1}, tree[1])
+}
+
+func TestBuildTree_Nested(t *testing.T) {
+	tree := BuildTree([]LabeledPrefix{
+		labeled("site", "2001:db8::/32"),
+		labeled("vpc", "2001:db8::/48"),
+		labeled("subnet", "2001:db8::/64"),
+	})
+
+	require.Len(t, tree, 1)
+	vpc := tree[0].Children[0]
+	assert.Equal(t, "vpc", vpc.Label)
+	assert.Equal(t, 1.0/65536, tree[0].Utilization)
+	assert.Equal(t, 1.0/65536, vpc.Utilization)
+	assert.Equal(t, "subnet", vpc.Children[0].Label)
+	assert.Len(t, vpc.Children, 17)
+}
+
+func TestRenderTree_Text(t *testing.T) {
+	tree := BuildTree([]LabeledPrefix{
+		labeled("site", "10.0.0.0/23"),
+		labeled("web", "10.0.0.0/24"),
+	})
+
+	out, err := RenderTree(tree, TreeText)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"10.0.0.0/23      site    [##########..........]   50.0%",
+		"├── 10.0.0.0/24  web     [####################]  100.0%",
+		"└── 10.0.1.0/24  (free)  [....................]    0.0%",
+		"",
+	}, "\n"), out)
+}
+
+func TestRenderTree_SVG(t *testing.T) {
+	tree := BuildTree([]LabeledPrefix{labeled("<lab>", "10.0.0.0/24")})
+
+	svg, err := RenderTree(tree, TreeSVG)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Contains(t, svg, "&lt;lab&gt;")
+	assert.Contains(t, svg, `width="200.0"`)
+
+	page, err := RenderTree(tree, TreeHTML)
+	require.NoError(t, err)
+	assert.Contains(t, page, "<!DOCTYPE html>")
+	assert.Contains(t, page, svg)
+
+	_, err = RenderTree(tree, "pdf")
+	assert.EqualError(t, err, `unknown tree format "pdf"`)
+}
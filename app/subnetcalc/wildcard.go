@@ -0,0 +1,101 @@
+package subnetcalc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net/netip"
+)
+
+// Wildcard is a Cisco-style address and wildcard mask pair. Bits set in the
+// wildcard are "don't care"; unlike a CIDR prefix they need not be
+// contiguous, so 10.0.5.0 0.0.250.0 is valid and matches addresses whose
+// third octet only agrees with 5 in bits 0 and 2.
+type Wildcard struct {
+	base uint32
+	mask uint32
+}
+
+// NewWildcard returns the wildcard matching addr under the wildcard mask.
+// Bits of addr under don't-care bits are ignored.
+func NewWildcard(addr, wildcard netip.Addr) (Wildcard, error) {
+	if !addr.Is4() || !wildcard.Is4() {
+		return Wildcard{}, fmt.Errorf("wildcard masks require IPv4 addresses")
+	}
+	mask := addrToUint32(wildcard)
+	return Wildcard{base: addrToUint32(addr) &^ mask, mask: mask}, nil
+}
+
+// Address returns the base address with the don't-care bits cleared.
+func (w Wildcard) Address() netip.Addr { return uint32ToAddr(w.base) }
+
+// Mask returns the wildcard mask.
+func (w Wildcard) Mask() netip.Addr { return uint32ToAddr(w.mask) }
+
+// Count returns the number of addresses the wildcard matches.
+func (w Wildcard) Count() uint64 { return 1 << bits.OnesCount32(w.mask) }
+
+// Matches reports whether addr matches the wildcard.
+func (w Wildcard) Matches(addr netip.Addr) bool {
+	return addr.Is4() && addrToUint32(addr)&^w.mask == w.base
+}
+
+// Prefix returns the equivalent CIDR prefix when the don't-care bits are
+// contiguous host bits.
+func (w Wildcard) Prefix() (netip.Prefix, bool) {
+	if w.mask&(w.mask+1) != 0 {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(w.Address(), 32-bits.OnesCount32(w.mask)), true
+}
+
+// PrefixCount returns the number of prefixes Prefixes decomposes the match
+// set into.
+func (w Wildcard) PrefixCount() uint64 {
+	return 1 << (bits.OnesCount32(w.mask) - bits.TrailingZeros32(^w.mask))
+}
+
+// Addresses lists the matching addresses in ascending order, at most limit
+// of them.
+func (w Wildcard) Addresses(limit int) []netip.Addr {
+	var addrs []netip.Addr
+	w.eachSubset(w.mask, limit, func(s uint32) {
+		addrs = append(addrs, uint32ToAddr(w.base|s))
+	})
+	return addrs
+}
+
+// Prefixes decomposes the match set into the fewest CIDR prefixes, at most
+// limit of them, in ascending order. Trailing don't-care bits become the host
+// bits of each prefix; every other don't-care bit doubles the prefix count.
+func (w Wildcard) Prefixes(limit int) []netip.Prefix {
+	host := bits.TrailingZeros32(^w.mask)
+	var prefixes []netip.Prefix
+	w.eachSubset(w.mask&^(1<<host-1), limit, func(s uint32) {
+		prefixes = append(prefixes, netip.PrefixFrom(uint32ToAddr(w.base|s), 32-host))
+	})
+	return prefixes
+}
+
+// eachSubset calls fn with every subset of the bits in set in ascending
+// numeric order, stopping after limit calls.
+func (w Wildcard) eachSubset(set uint32, limit int, fn func(uint32)) {
+	var s uint32
+	for n := 0; n < limit; n++ {
+		fn(s)
+		s = (s - set) & set
+		if s == 0 {
+			return
+		}
+	}
+}
+
+// String returns the wildcard in Cisco "address wildcard" form.
+func (w Wildcard) String() string {
+	return w.Address().String() + " " + w.Mask().String()
+}
+
+func addrToUint32(addr netip.Addr) uint32 {
+	b := addr.As4()
+	return binary.BigEndian.Uint32(b[:])
+}
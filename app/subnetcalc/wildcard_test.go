@@ -0,0 +1,72 @@
+package subnetcalc
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustWildcard(t *testing.T, addr, mask string) Wildcard {
+	t.Helper()
+	w, err := NewWildcard(netip.MustParseAddr(addr), netip.MustParseAddr(mask))
+	require.NoError(t, err)
+	return w
+}
+
+func TestWildcard_Discontiguous(t *testing.T) {
+	w := mustWildcard(t, "10.0.7.0", "0.0.250.0")
+
+	assert.Equal(t, "10.0.5.0 0.0.250.0", w.String())
+	assert.Equal(t, uint64(64), w.Count())
+	assert.Equal(t, uint64(64), w.PrefixCount())
+	_, ok := w.Prefix()
+	assert.False(t, ok)
+
+	assert.True(t, w.Matches(netip.MustParseAddr("10.0.5.0")))
+	assert.True(t, w.Matches(netip.MustParseAddr("10.0.255.0")))
+	assert.False(t, w.Matches(netip.MustParseAddr("10.0.4.0")))
+	assert.False(t, w.Matches(netip.MustParseAddr("10.0.5.1")))
+	assert.False(t, w.Matches(netip.MustParseAddr("2001:db8::1")))
+
+	assert.Equal(t, []netip.Addr{
+		netip.MustParseAddr("10.0.5.0"),
+		netip.MustParseAddr("10.0.7.0"),
+		netip.MustParseAddr("10.0.13.0"),
+	}, w.Addresses(3))
+}
+
+func TestWildcard_Prefixes(t *testing.T) {
+	// Odd third octets: bit 0 fixed, the rest of the octet and the whole
+	// fourth octet free.
+	w := mustWildcard(t, "192.168.1.0", "0.0.254.255")
+	assert.Equal(t, uint64(128), w.PrefixCount())
+	prefixes := w.Prefixes(1000)
+	require.Len(t, prefixes, 128)
+	assert.Equal(t, mustPrefixes(t, "192.168.1.0/24", "192.168.3.0/24"), prefixes[:2])
+	assert.Equal(t, netip.MustParsePrefix("192.168.255.0/24"), prefixes[127])
+
+	assert.Equal(t, mustPrefixes(t, "192.168.1.0/24"), w.Prefixes(1))
+}
+
+func TestWildcard_Contiguous(t *testing.T) {
+	w := mustWildcard(t, "172.16.38.94", "0.0.0.31")
+	prefix, ok := w.Prefix()
+	require.True(t, ok)
+	assert.Equal(t, netip.MustParsePrefix("172.16.38.64/27"), prefix)
+	assert.Equal(t, []netip.Prefix{prefix}, w.Prefixes(10))
+	assert.Len(t, w.Addresses(100), 32)
+
+	all := mustWildcard(t, "1.2.3.4", "255.255.255.255")
+	assert.Equal(t, uint64(1)<<32, all.Count())
+	assert.Equal(t, mustPrefixes(t, "0.0.0.0/0"), all.Prefixes(10))
+
+	host := mustWildcard(t, "1.2.3.4", "0.0.0.0")
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("1.2.3.4")}, host.Addresses(10))
+}
+
+func TestNewWildcard_IPv6(t *testing.T) {
+	_, err := NewWildcard(netip.MustParseAddr("2001:db8::"), netip.MustParseAddr("0.0.0.255"))
+	assert.EqualError(t, err, "wildcard masks require IPv4 addresses")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var aclCmd = &cobra.Command{
+	Use:   "acl <cidr>...",
+	Short: "Render firewall rules for prefixes",
+	Long: `Render permit or deny rules that match traffic sourced from the given prefixes.
+
+Supported formats are iptables, nftables, cisco, junos and aws-sg. Cisco rules
+use wildcard masks; aws-sg prints the --ip-permissions JSON for
+"aws ec2 authorize-security-group-ingress" and cannot express deny rules.`,
+	Example: `# Cisco extended ACL denying two ranges
+snc acl --format cisco --deny --name BLOCKLIST 10.0.0.0/8 192.168.0.0/16`,
+	ValidArgsFunction: completePrefixes,
+	Args:              cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var opts subnetcalc.ACLOptions
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		opts.Format = subnetcalc.ACLFormat(format)
+		if opts.Deny, err = cmd.Flags().GetBool("deny"); err != nil {
+			return err
+		}
+		if opts.Name, err = cmd.Flags().GetString("name"); err != nil {
+			return err
+		}
+
+		prefixes, err := parsePrefixArgs(args)
+		if err != nil {
+			return err
+		}
+
+		acl, err := subnetcalc.RenderACL(prefixes, opts)
+		if err != nil {
+			return fmt.Errorf("error rendering ACL: %s", err)
+		}
+		fmt.Print(acl)
+		return nil
+	},
+}
+
+func init() {
+	aclCmd.Flags().StringP("format", "f", string(subnetcalc.ACLIptables), fmt.Sprintf("rule format %v", subnetcalc.ACLFormats))
+	aclCmd.Flags().Bool("deny", false, "render deny rules instead of permit rules")
+	aclCmd.Flags().String("name", "", "ACL, filter or chain name (format default when empty)")
+	rootCmd.AddCommand(aclCmd)
+}
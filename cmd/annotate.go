@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// addAnnotateFlag adds the --annotate flag to flags.
+func addAnnotateFlag(flags *pflag.FlagSet) {
+	flags.StringSlice("annotate", nil, "annotate prefixes from offline MaxMind DB (.mmdb) or RIR delegated-stats files")
+}
+
+// loadAnnotator opens the files named by --annotate. It returns nil when the
+// flag is not set. The caller closes the annotators when done.
+func loadAnnotator(cmd *cobra.Command) (subnetcalc.Annotators, error) {
+	paths, err := cmd.Flags().GetStringSlice("annotate")
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	annotators := make(subnetcalc.Annotators, 0, len(paths))
+	for _, path := range paths {
+		a, err := subnetcalc.LoadAnnotator(path)
+		if err != nil {
+			annotators.Close()
+			return nil, fmt.Errorf("error loading %s: %s", path, err)
+		}
+		annotators = append(annotators, a)
+	}
+	return annotators, nil
+}
+
+// annotationSuffix returns the annotation of prefix as text to print after
+// it, or "" when there is none.
+func annotationSuffix(annotators subnetcalc.Annotators, prefix netip.Prefix) string {
+	a, ok := annotators.Annotate(prefix)
+	if !ok {
+		return ""
+	}
+	return "  " + annotationText(a)
+}
+
+// annotationText formats an annotation on one line.
+func annotationText(a subnetcalc.Annotation) string {
+	var parts []string
+	if a.ASN != 0 {
+		parts = append(parts, fmt.Sprintf("AS%d", a.ASN))
+	}
+	for _, s := range []string{a.Organization, a.Country, a.Registry} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// writeAnnotation writes the annotation of a result to w in the layout of
+// the root command.
+func writeAnnotation(w io.Writer, a *subnetcalc.Annotation) {
+	if a == nil {
+		fmt.Fprintf(w, "Annotation:         none\n")
+		return
+	}
+	fmt.Fprintf(w, "Annotated Network:  %s\n", a.Network)
+	if a.ASN != 0 {
+		fmt.Fprintf(w, "ASN:                AS%d\n", a.ASN)
+	}
+	if a.Organization != "" {
+		fmt.Fprintf(w, "Organization:       %s\n", a.Organization)
+	}
+	if a.Country != "" {
+		fmt.Fprintf(w, "Country:            %s\n", a.Country)
+	}
+	if a.Registry != "" {
+		fmt.Fprintf(w, "Registry:           %s\n", a.Registry)
+	}
+}
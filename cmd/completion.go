@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for snc.
+
+Besides commands and flags, the completions know about prefixes: after an
+address and a slash they offer every prefix length with its subnet mask or
+size, and a complete address is offered with a trailing slash.`,
+	Example: `# load completions into the current bash session
+source <(snc completion bash)
+
+# install zsh completions
+snc completion zsh > "${fpath[1]}/_snc"
+
+# install fish completions
+snc completion fish > ~/.config/fish/completions/snc.fish
+
+# load completions into the current PowerShell session
+snc completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		switch args[0] {
+		case "bash":
+			return root.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return root.GenZshCompletion(os.Stdout)
+		case "fish":
+			return root.GenFishCompletion(os.Stdout, true)
+		default:
+			return root.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+// completePrefix completes the first argument of a command as a prefix; see
+// prefixCompletions.
+func completePrefix(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return prefixCompletions(toComplete, false)
+}
+
+// completeZonedPrefix is completePrefix for commands that also accept an IPv6
+// zone, such as fe80::1%eth0/64.
+func completeZonedPrefix(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return prefixCompletions(toComplete, true)
+}
+
+// completePrefixes completes every argument of a command as a prefix.
+func completePrefixes(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return prefixCompletions(toComplete, false)
+}
+
+// prefixCompletions suggests the prefix lengths that can follow an address
+// and a slash, described by their subnet mask for IPv4 and by their size for
+// IPv6. A complete address without a slash is offered with one appended.
+// Addresses with a zone only get completions when zoned is set, since most
+// commands reject them.
+func prefixCompletions(toComplete string, zoned bool) ([]cobra.Completion, cobra.ShellCompDirective) {
+	addrPart, bitsPart, hasBits := strings.Cut(toComplete, "/")
+	addr, err := netip.ParseAddr(addrPart)
+	if err != nil || (addr.Zone() != "" && !zoned) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if !hasBits {
+		return []cobra.Completion{toComplete + "/"}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []cobra.Completion
+	for bits := 0; bits <= addr.BitLen(); bits++ {
+		length := strconv.Itoa(bits)
+		if !strings.HasPrefix(length, bitsPart) {
+			continue
+		}
+		description := fmt.Sprintf("2^%d addresses", 128-bits)
+		if bits == 128 {
+			description = "1 address"
+		}
+		if addr.Is4() {
+			info, err := subnetcalc.CalcSubnetInfo(netip.PrefixFrom(addr, bits))
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			description = fmt.Sprintf("%s, %d %s", info.SubnetMask, info.TotalIP, plural(int(info.TotalIP), "address", "addresses"))
+		}
+		completions = append(completions, cobra.CompletionWithDesc(addrPart+"/"+length, description))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// formatNames converts a list of string-typed values, such as output formats
+// or providers, to completion candidates.
+func formatNames[T ~string](values []T) []cobra.Completion {
+	names := make([]cobra.Completion, 0, len(values))
+	for _, v := range values {
+		names = append(names, string(v))
+	}
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixCompletions(t *testing.T) {
+	tests := []struct {
+		name        string
+		toComplete  string
+		zoned       bool
+		completions []cobra.Completion
+		directive   cobra.ShellCompDirective
+	}{
+		{
+			name:        "address gets a slash",
+			toComplete:  "10.0.0.0",
+			completions: []cobra.Completion{"10.0.0.0/"},
+			directive:   cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:       "IPv4 lengths",
+			toComplete: "10.0.0.0/3",
+			completions: []cobra.Completion{
+				"10.0.0.0/3\t224.0.0.0, 536870912 addresses",
+				"10.0.0.0/30\t255.255.255.252, 4 addresses",
+				"10.0.0.0/31\t255.255.255.254, 2 addresses",
+				"10.0.0.0/32\t255.255.255.255, 1 address",
+			},
+			directive: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:        "IPv6 lengths",
+			toComplete:  "2001:db8::/12",
+			completions: []cobra.Completion{"2001:db8::/12\t2^116 addresses", "2001:db8::/120\t2^8 addresses", "2001:db8::/121\t2^7 addresses", "2001:db8::/122\t2^6 addresses", "2001:db8::/123\t2^5 addresses", "2001:db8::/124\t2^4 addresses", "2001:db8::/125\t2^3 addresses", "2001:db8::/126\t2^2 addresses", "2001:db8::/127\t2^1 addresses", "2001:db8::/128\t1 address"},
+			directive:   cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:       "single matching length",
+			toComplete: "10.0.0.0/4",
+			completions: []cobra.Completion{
+				"10.0.0.0/4\t240.0.0.0, 268435456 addresses",
+			},
+			directive: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{name: "length out of range", toComplete: "10.0.0.0/33", directive: cobra.ShellCompDirectiveNoFileComp},
+		{name: "partial address", toComplete: "10.0", directive: cobra.ShellCompDirectiveNoFileComp},
+		{name: "zoned address", toComplete: "fe80::1%eth0", directive: cobra.ShellCompDirectiveNoFileComp},
+		{name: "zoned prefix", toComplete: "fe80::1%eth0/6", directive: cobra.ShellCompDirectiveNoFileComp},
+		{
+			name:        "zoned address where zones are accepted",
+			toComplete:  "fe80::1%eth0",
+			zoned:       true,
+			completions: []cobra.Completion{"fe80::1%eth0/"},
+			directive:   cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:        "zoned prefix where zones are accepted",
+			toComplete:  "fe80::1%eth0/64",
+			zoned:       true,
+			completions: []cobra.Completion{"fe80::1%eth0/64\t2^64 addresses"},
+			directive:   cobra.ShellCompDirectiveNoFileComp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completions, directive := prefixCompletions(tt.toComplete, tt.zoned)
+			assert.Equal(t, tt.completions, completions)
+			assert.Equal(t, tt.directive, directive)
+		})
+	}
+}
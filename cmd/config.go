@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+	"go.yaml.in/yaml/v3"
+)
+
+// colorModes lists the values of the color setting.
+var colorModes = []string{"auto", "always", "never"}
+
+// setting is a default that can come from the config file, an SNC_*
+// environment variable or a flag, in increasing order of precedence. scope
+// names the commands that use it, for config show.
+type setting struct {
+	key      string
+	env      string
+	flag     string
+	scope    string
+	value    string
+	source   string
+	err      error
+	validate func(string) error
+}
+
+// newSettings returns every setting at its built-in default.
+func newSettings() []*setting {
+	return []*setting{
+		{key: "output", env: "SNC_OUTPUT", flag: "output", scope: "snc, lint", value: string(subnetcalc.OutputText), validate: func(v string) error {
+			if !slices.Contains(subnetcalc.OutputFormats, subnetcalc.OutputFormat(v)) {
+				return fmt.Errorf("unknown output format %q", v)
+			}
+			return nil
+		}},
+		{key: "color", env: "SNC_COLOR", flag: "color", scope: "all commands", value: "auto", validate: func(v string) error {
+			if !slices.Contains(colorModes, v) {
+				return fmt.Errorf("unknown color mode %q", v)
+			}
+			return nil
+		}},
+		{key: "provider", env: "SNC_PROVIDER", flag: "provider", scope: "snc", validate: func(v string) error {
+			if v == "" {
+				return nil
+			}
+			_, err := subnetcalc.ParseProvider(v)
+			return err
+		}},
+		{key: "strict", env: "SNC_STRICT", flag: "strict", scope: "snc", value: "false", validate: func(v string) error {
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+			return nil
+		}},
+	}
+}
+
+// fileConfig is the layout of the config file. Unset fields keep their
+// default.
+type fileConfig struct {
+	Output   *string `yaml:"output"`
+	Color    *string `yaml:"color"`
+	Provider *string `yaml:"provider"`
+	Strict   *bool   `yaml:"strict"`
+}
+
+func (c fileConfig) values() map[string]string {
+	values := make(map[string]string)
+	for key, v := range map[string]*string{"output": c.Output, "color": c.Color, "provider": c.Provider} {
+		if v != nil {
+			values[key] = *v
+		}
+	}
+	if c.Strict != nil {
+		values["strict"] = strconv.FormatBool(*c.Strict)
+	}
+	return values
+}
+
+// configPath returns the config file location: $SNC_CONFIG if set, otherwise
+// snc/config.yaml under $XDG_CONFIG_HOME or ~/.config.
+func configPath() (string, error) {
+	if path := os.Getenv("SNC_CONFIG"); path != "" {
+		return path, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "snc", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "snc", "config.yaml"), nil
+}
+
+// readConfigFile decodes the config file at path. A missing file is not an
+// error and yields no values.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return c.values(), nil
+}
+
+// resolveSettings works out the effective value and source of every setting
+// for cmd: built-in default, then the config file, then the environment,
+// then flags given on the command line. Invalid values are recorded in the
+// err field of their setting rather than returned, since only the commands
+// using a setting should fail on it.
+func resolveSettings(cmd *cobra.Command) ([]*setting, string, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, "", err
+	}
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	settings := newSettings()
+	for _, s := range settings {
+		s.source = "default"
+		if v, ok := values[s.key]; ok {
+			s.value, s.source = v, "config file"
+		}
+		if v, ok := os.LookupEnv(s.env); ok {
+			s.value, s.source = v, s.env
+		}
+		if f := cmd.Flags().Lookup(s.flag); f != nil && f.Changed {
+			s.value, s.source = f.Value.String(), "--"+s.flag
+		}
+		if err := s.validate(s.value); err != nil {
+			s.err = fmt.Errorf("%s (from %s): %s", s.key, s.source, err)
+		}
+	}
+	return settings, path, nil
+}
+
+// applySettings makes the configured defaults the values of the flags of cmd
+// that were not given on the command line, and sets up colored output. Only
+// settings with a flag on cmd are checked, so a bad value for one command
+// does not break the others. The flags are not marked as changed, which lets
+// commands tell a configured default from an explicit flag.
+func applySettings(cmd *cobra.Command) error {
+	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return nil
+	}
+	settings, _, err := resolveSettings(cmd)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	for _, s := range settings {
+		f := cmd.Flags().Lookup(s.flag)
+		if f == nil {
+			continue
+		}
+		if s.err != nil {
+			cmd.SilenceUsage = true
+			return s.err
+		}
+		if !f.Changed && s.source != "default" {
+			if err := f.Value.Set(s.value); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%s (from %s): %s", s.key, s.source, err)
+			}
+		}
+		if s.key == "color" {
+			applyColor(s.value)
+		}
+	}
+	return nil
+}
+
+// applyColor forces colored output on or off; "auto" leaves the terminal
+// detection of the color package in place.
+func applyColor(mode string) {
+	switch mode {
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	}
+}
+
+// warnf prints a warning line to w, with the prefix highlighted when color
+// is enabled.
+func warnf(w io.Writer, format string, args ...any) {
+	fmt.Fprintf(w, "%s %s\n", color.YellowString("warning:"), fmt.Sprintf(format, args...))
+}
+
+// plural returns singular when n is 1 and pluralForm otherwise, for counts
+// in messages such as "1 problem found".
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the snc configuration",
+	Long: `Inspect the snc configuration.
+
+Defaults are read from ~/.config/snc/config.yaml ($XDG_CONFIG_HOME/snc/config.yaml
+when set, or the file named by $SNC_CONFIG) and from SNC_* environment
+variables. Environment variables override the file, and flags override both.
+
+  output    SNC_OUTPUT    output format of snc <prefix> and lint (not of tree
+                          or acl, whose formats differ)
+  color     SNC_COLOR     colored output of every command
+  provider  SNC_PROVIDER  cloud provider of snc <prefix>; skipped for prefixes
+                          the provider does not allow, such as IPv6
+  strict    SNC_STRICT    strict mode of snc <prefix>
+
+A setting is only checked by the commands that use it, so an invalid value
+does not break the others; "snc config show" lists every invalid value.
+
+Example config file:
+
+  output: json
+  color: never
+  provider: aws
+  strict: true`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		settings, _, err := resolveSettings(cmd)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		for _, s := range settings {
+			if s.key == "color" && s.err == nil {
+				applyColor(s.value)
+			}
+		}
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration and where each value came from",
+	Long: `Show the effective configuration and where each value came from.
+
+Invalid values are listed too, followed by a warning naming their source.`,
+	Example: `snc config show
+SNC_OUTPUT=yaml snc config show`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, path, err := resolveSettings(cmd)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Config file:        %s\n\n", path)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE\tAPPLIES TO")
+		for _, s := range settings {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.key, s.value, s.source, s.scope)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		for _, s := range settings {
+			if s.err != nil {
+				warnf(os.Stderr, "%s", s.err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two address plans",
+	Long: `Compare two address plans.
+
+Each file lists one prefix per line followed by an optional label. Subnets are
+matched by label, or by prefix when unlabeled, and reported as added, removed,
+grown, shrunk or renumbered. Overlaps that exist only in the new plan are
+listed as well and make the command exit non-zero.`,
+	Example: `snc diff plan-v1.txt plan-v2.txt`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldPlan, err := readPlanFile(args[0])
+		if err != nil {
+			return err
+		}
+		newPlan, err := readPlanFile(args[1])
+		if err != nil {
+			return err
+		}
+
+		diff := subnetcalc.DiffPlans(oldPlan, newPlan)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range diff.Changes {
+			fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\n", changeMarker(c.Kind), c.Kind, c.Label, formatPrefix(c.Old), formatPrefix(c.New))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		for _, c := range diff.NewOverlaps {
+			fmt.Printf("new overlap: %s (%s) overlaps %s (%s)\n", c.A.Label, c.A.Prefix, c.B.Label, c.B.Prefix)
+		}
+		if len(diff.NewOverlaps) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d new %s", len(diff.NewOverlaps), plural(len(diff.NewOverlaps), "overlap", "overlaps"))
+		}
+		return nil
+	},
+}
+
+func readPlanFile(path string) ([]subnetcalc.LabeledPrefix, error) {
+	lines, err := scanPrefixLines(path)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]subnetcalc.LabeledPrefix, 0, len(lines))
+	for _, l := range lines {
+		plan = append(plan, subnetcalc.LabeledPrefix{Label: l.label, Prefix: l.prefix})
+	}
+	return plan, nil
+}
+
+func changeMarker(kind subnetcalc.ChangeKind) string {
+	switch kind {
+	case subnetcalc.ChangeAdded:
+		return "+"
+	case subnetcalc.ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func formatPrefix(prefix netip.Prefix) string {
+	if !prefix.IsValid() {
+		return "-"
+	}
+	return prefix.String()
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// dockerNetwork is the part of `docker network inspect` output we need.
+type dockerNetwork struct {
+	Name string `json:"Name"`
+	IPAM struct {
+		Config []struct {
+			Subnet string `json:"Subnet"`
+		} `json:"Config"`
+	} `json:"IPAM"`
+}
+
+// dockerDaemonConfig is the part of daemon.json that assigns addresses.
+type dockerDaemonConfig struct {
+	BIP                 string `json:"bip"`
+	FixedCIDR           string `json:"fixed-cidr"`
+	FixedCIDRv6         string `json:"fixed-cidr-v6"`
+	DefaultAddressPools []struct {
+		Base string `json:"base"`
+		Size int    `json:"size"`
+	} `json:"default-address-pools"`
+}
+
+var dockerCmd = &cobra.Command{
+	Use:   "docker",
+	Short: "Docker network address checks",
+}
+
+var dockerCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report Docker networks that overlap host routes or other ranges",
+	Long: `Report Docker networks that overlap host routes or other ranges.
+
+--networks reads the JSON printed by "docker network inspect" ("-" for stdin)
+and --daemon reads daemon.json for bip, fixed-cidr and default-address-pools.
+Every prefix is checked against the prefix list files given with --ranges,
+one prefix and optional label per line. Docker networks that overlap each
+other are reported as well. The command exits non-zero on any conflict.`,
+	Example: `docker network inspect $(docker network ls -q) | snc docker check --networks - --ranges corp.txt
+snc docker check --daemon /etc/docker/daemon.json --ranges routes.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		networksPath, err := cmd.Flags().GetString("networks")
+		if err != nil {
+			return err
+		}
+		daemonPath, err := cmd.Flags().GetString("daemon")
+		if err != nil {
+			return err
+		}
+		rangePaths, err := cmd.Flags().GetStringSlice("ranges")
+		if err != nil {
+			return err
+		}
+		if networksPath == "" && daemonPath == "" {
+			return errors.New("at least one of --networks or --daemon is required")
+		}
+
+		var networks, docker []subnetcalc.LabeledPrefix
+		if networksPath != "" {
+			if networks, err = readDockerNetworks(networksPath); err != nil {
+				return err
+			}
+		}
+		docker = append(docker, networks...)
+		if daemonPath != "" {
+			pools, err := readDockerDaemonConfig(daemonPath)
+			if err != nil {
+				return err
+			}
+			docker = append(docker, pools...)
+		}
+
+		var ranges []subnetcalc.LabeledPrefix
+		for _, path := range rangePaths {
+			prefixes, err := readLabeledPrefixFile(path)
+			if err != nil {
+				return err
+			}
+			ranges = append(ranges, prefixes...)
+		}
+
+		conflicts := subnetcalc.FindConflicts(networks)
+		conflicts = append(conflicts, subnetcalc.FindConflictsBetween(docker, ranges)...)
+		for _, c := range conflicts {
+			fmt.Printf("%s (%s) overlaps %s (%s)\n", c.A.Label, c.A.Prefix, c.B.Label, c.B.Prefix)
+		}
+		if len(conflicts) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d %s found", len(conflicts), plural(len(conflicts), "conflict", "conflicts"))
+		}
+		fmt.Printf("no conflicts among %d Docker prefixes and %d ranges\n", len(docker), len(ranges))
+		return nil
+	},
+}
+
+func readDockerNetworks(path string) ([]subnetcalc.LabeledPrefix, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	var networks []dockerNetwork
+	if err := json.Unmarshal(data, &networks); err != nil {
+		var single dockerNetwork
+		if json.Unmarshal(data, &single) != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		networks = []dockerNetwork{single}
+	}
+
+	var prefixes []subnetcalc.LabeledPrefix
+	for _, network := range networks {
+		for _, config := range network.IPAM.Config {
+			if config.Subnet == "" {
+				continue
+			}
+			prefix, err := netip.ParsePrefix(config.Subnet)
+			if err != nil {
+				return nil, fmt.Errorf("%s: network %s: %s", path, network.Name, err)
+			}
+			prefixes = append(prefixes, subnetcalc.LabeledPrefix{Label: "network " + network.Name, Prefix: prefix})
+		}
+	}
+	return prefixes, nil
+}
+
+func readDockerDaemonConfig(path string) ([]subnetcalc.LabeledPrefix, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var config dockerDaemonConfig
+	if err := json.NewDecoder(f).Decode(&config); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	var prefixes []subnetcalc.LabeledPrefix
+	add := func(label, value string) error {
+		if value == "" {
+			return nil
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %s", path, label, err)
+		}
+		prefixes = append(prefixes, subnetcalc.LabeledPrefix{Label: label, Prefix: prefix.Masked()})
+		return nil
+	}
+	for _, field := range []struct{ label, value string }{
+		{"daemon bip", config.BIP},
+		{"daemon fixed-cidr", config.FixedCIDR},
+		{"daemon fixed-cidr-v6", config.FixedCIDRv6},
+	} {
+		if err := add(field.label, field.value); err != nil {
+			return nil, err
+		}
+	}
+	for _, pool := range config.DefaultAddressPools {
+		if err := add(fmt.Sprintf("default-address-pool (size /%d)", pool.Size), pool.Base); err != nil {
+			return nil, err
+		}
+	}
+	return prefixes, nil
+}
+
+func init() {
+	dockerCheckCmd.Flags().String("networks", "", "file with docker network inspect JSON output (\"-\" for stdin)")
+	dockerCheckCmd.Flags().String("daemon", "", "path to daemon.json")
+	dockerCheckCmd.Flags().StringSlice("ranges", nil, "prefix list files with host routes or corporate ranges")
+	dockerCmd.AddCommand(dockerCheckCmd)
+	rootCmd.AddCommand(dockerCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var eui64Cmd = &cobra.Command{
+	Use:   "eui64 <ipv6-prefix> <mac> | eui64 <ipv6-address>",
+	Short: "Compute or decode EUI-64 (SLAAC) IPv6 addresses",
+	Long: `Compute or decode EUI-64 (SLAAC) IPv6 addresses.
+
+With a /64 prefix and a MAC address, print the SLAAC address a host with that
+MAC configures in the prefix, its link-local address and the interface
+identifier. With a single IPv6 address, print the MAC address embedded in its
+EUI-64 interface identifier.`,
+	Example: `snc eui64 2001:db8:1:2::/64 00:1a:2b:3c:4d:5e
+snc eui64 fe80::21a:2bff:fe3c:4d5e`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			addr, err := netip.ParseAddr(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address: %s", err)
+			}
+			mac, err := subnetcalc.MACFromEUI64(addr)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("MAC Address:        %s\n", mac)
+			return nil
+		}
+
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+		mac, err := net.ParseMAC(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid MAC address: %s", err)
+		}
+
+		addr, err := subnetcalc.EUI64Address(prefix, mac)
+		if err != nil {
+			return err
+		}
+		linkLocal, err := subnetcalc.LinkLocalAddress(mac)
+		if err != nil {
+			return err
+		}
+		id, err := subnetcalc.EUI64InterfaceID(mac)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("SLAAC Address:      %s\n", addr)
+		fmt.Printf("Link-Local Address: %s\n", linkLocal)
+		fmt.Printf("Interface ID:       %x:%x:%x:%x\n", id[0:2], id[2:4], id[4:6], id[6:8])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eui64Cmd)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// openInput opens path for reading, treating "-" as stdin.
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// readInput reads all of path, treating "-" as stdin.
+func readInput(path string) ([]byte, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// inputPath returns the optional file argument, defaulting to stdin.
+func inputPath(args []string) string {
+	if len(args) == 0 {
+		return "-"
+	}
+	return args[0]
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// prefixLine is a prefix read from a prefix list file, with the line it was
+// found on and the rest of that line as its label.
+type prefixLine struct {
+	line   int
+	prefix netip.Prefix
+	label  string
+}
+
+// scanPrefixLines reads the prefix list at path ("-" for stdin), accepting
+// bare addresses as host prefixes. See subnetcalc.ReadPrefixList for the
+// format.
+func scanPrefixLines(path string) ([]prefixLine, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := subnetcalc.ReadPrefixList(f)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]prefixLine, 0, len(entries))
+	for _, e := range entries {
+		prefix, err := subnetcalc.ParsePrefixOrAddr(e.Token)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, e.Line, err)
+		}
+		lines = append(lines, prefixLine{line: e.Line, prefix: prefix, label: e.Label})
+	}
+	return lines, nil
+}
+
+// readPrefixFile reads the prefixes of a prefix list file, dropping labels.
+func readPrefixFile(path string) ([]netip.Prefix, error) {
+	lines, err := scanPrefixLines(path)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := make([]netip.Prefix, 0, len(lines))
+	for _, l := range lines {
+		prefixes = append(prefixes, l.prefix)
+	}
+	return prefixes, nil
+}
+
+// readLabeledPrefixFile reads the labeled prefixes of a prefix list file.
+// Lines without a label are labeled with their file and line number.
+func readLabeledPrefixFile(path string) ([]subnetcalc.LabeledPrefix, error) {
+	lines, err := scanPrefixLines(path)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := make([]subnetcalc.LabeledPrefix, 0, len(lines))
+	for _, l := range lines {
+		label := l.label
+		if label == "" {
+			label = fmt.Sprintf("%s:%d", path, l.line)
+		}
+		prefixes = append(prefixes, subnetcalc.LabeledPrefix{Label: label, Prefix: l.prefix})
+	}
+	return prefixes, nil
+}
+
+// parsePrefixArgs parses every argument with subnetcalc.ParsePrefixOrAddr.
+func parsePrefixArgs(args []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(args))
+	for _, arg := range args {
+		prefix, err := subnetcalc.ParsePrefixOrAddr(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix: %s", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
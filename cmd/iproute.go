@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// routeTypes are the route type keywords that may precede the destination
+// in `ip route` output.
+var routeTypes = map[string]bool{
+	"unicast": true, "local": true, "broadcast": true, "multicast": true, "anycast": true,
+	"blackhole": true, "unreachable": true, "prohibit": true, "throw": true, "nat": true,
+}
+
+var fromIPRouteCmd = &cobra.Command{
+	Use:   "from-ip-route [file]",
+	Short: "Analyze the prefixes in saved ip route output",
+	Long: `Analyze the prefixes in saved "ip route" output.
+
+Reads from the file or stdin and prints the subnet information of every
+route destination. Both "ip route" and "ip -6 route" output are understood.
+
+A default route without a gateway, such as "default dev wg0", takes the
+family of the other routes in the capture; when that is ambiguous its
+columns are left empty.`,
+	Example: `ip route show table all > routes.txt
+snc from-ip-route < routes.txt`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		lines, err := readLines(inputPath(args))
+		if err != nil {
+			return err
+		}
+
+		var routes []route
+		for _, line := range lines {
+			r, ok, err := parseRouteLine(line)
+			if err != nil {
+				return err
+			}
+			if ok {
+				routes = append(routes, r)
+			}
+		}
+		resolveDefaultRoutes(routes)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DESTINATION\tDEV\tNETWORK\tBROADCAST\tMASK\tTOTAL")
+		for _, r := range routes {
+			if !r.prefix.IsValid() {
+				fmt.Fprintf(w, "default\t%s\t-\t-\t-\t-\n", r.dev)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", r.prefix, r.dev, subnetColumns(r.prefix))
+		}
+		return w.Flush()
+	},
+}
+
+var fromIPAddrCmd = &cobra.Command{
+	Use:   "from-ip-addr [file]",
+	Short: "Analyze the addresses in saved ip addr output",
+	Long: `Analyze the addresses in saved "ip addr" output.
+
+Reads from the file or stdin, in either the default or the brief (ip -br addr)
+format, and prints the subnet information of every configured address. It
+flags addresses that are the network or broadcast address of their subnet and
+interfaces whose subnets overlap, exiting non-zero if any are found.`,
+	Example: `ip addr > addrs.txt
+snc from-ip-addr addrs.txt`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lines, err := readLines(inputPath(args))
+		if err != nil {
+			return err
+		}
+		addrs, err := parseIPAddr(lines)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "INTERFACE\tADDRESS\tNETWORK\tBROADCAST\tMASK\tTOTAL")
+		for _, a := range addrs {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", a.Interface, a.Address, subnetColumns(a.Address))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		issues := subnetcalc.CheckInterfaceAddresses(addrs)
+		for _, issue := range issues {
+			warnf(os.Stderr, "%s %s: %s", issue.Interface, issue.Address, issue.Problem)
+		}
+		if len(issues) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d %s found", len(issues), plural(len(issues), "issue", "issues"))
+		}
+		return nil
+	},
+}
+
+// route is a route read from `ip route` output. The prefix of a default
+// route is left unset when its address family cannot be told from the line.
+type route struct {
+	prefix netip.Prefix
+	dev    string
+}
+
+// parseRouteLine extracts the destination prefix and device of one
+// `ip route` line. ok is false for lines that carry no route.
+//
+// A default route takes its family from its gateway or source address, or
+// from the "pref" attribute only IPv6 routes have.
+func parseRouteLine(line string) (route, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || startsWithSpace(line) {
+		return route{}, false, nil
+	}
+	if routeTypes[fields[0]] && len(fields) > 1 {
+		fields = fields[1:]
+	}
+
+	r := route{dev: "-"}
+	var is4, is6 bool
+	for i := 1; i < len(fields); i++ {
+		switch fields[i] {
+		case "dev":
+			if i+1 < len(fields) {
+				r.dev = fields[i+1]
+			}
+		case "via", "src":
+			next := fields[i+1:]
+			if len(next) > 1 && (next[0] == "inet" || next[0] == "inet6") {
+				// "via inet6 fe80::1" names the gateway family explicitly.
+				next = next[1:]
+			}
+			if len(next) > 0 {
+				if addr, err := netip.ParseAddr(next[0]); err == nil {
+					is4, is6 = is4 || addr.Is4(), is6 || addr.Is6()
+				}
+			}
+		case "pref":
+			is6 = true
+		}
+	}
+
+	if fields[0] == "default" {
+		switch {
+		case is6 && !is4:
+			r.prefix = netip.MustParsePrefix("::/0")
+		case is4 && !is6:
+			r.prefix = netip.MustParsePrefix("0.0.0.0/0")
+		}
+		return r, true, nil
+	}
+	prefix, err := subnetcalc.ParsePrefixOrAddr(fields[0])
+	if err != nil {
+		return route{}, false, fmt.Errorf("invalid route %q: %s", line, err)
+	}
+	r.prefix = prefix
+	return r, true, nil
+}
+
+// resolveDefaultRoutes gives default routes of unknown family the family of
+// the other routes in the capture. They stay unresolved when the capture
+// mixes both families or has no other routes.
+func resolveDefaultRoutes(routes []route) {
+	var is4, is6 bool
+	for _, r := range routes {
+		if r.prefix.IsValid() {
+			is4, is6 = is4 || r.prefix.Addr().Is4(), is6 || r.prefix.Addr().Is6()
+		}
+	}
+	var family netip.Prefix
+	switch {
+	case is6 && !is4:
+		family = netip.MustParsePrefix("::/0")
+	case is4 && !is6:
+		family = netip.MustParsePrefix("0.0.0.0/0")
+	default:
+		return
+	}
+	for i := range routes {
+		if !routes[i].prefix.IsValid() {
+			routes[i].prefix = family
+		}
+	}
+}
+
+// parseIPAddr extracts the interface addresses from `ip addr` or
+// `ip -br addr` output.
+func parseIPAddr(lines []string) ([]subnetcalc.InterfaceAddress, error) {
+	var addrs []subnetcalc.InterfaceAddress
+	iface := ""
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if !startsWithSpace(line) {
+			if strings.HasSuffix(fields[0], ":") && len(fields) > 1 {
+				// "2: eth0: <BROADCAST,...>" starts a new interface block.
+				iface = interfaceName(fields[1])
+				continue
+			}
+			// "eth0  UP  192.168.1.10/24 fe80::1/64" is the brief format.
+			for _, field := range fields[min(2, len(fields)):] {
+				prefix, err := subnetcalc.ParsePrefixOrAddr(field)
+				if err != nil {
+					return nil, fmt.Errorf("invalid address %q: %s", field, err)
+				}
+				addrs = append(addrs, subnetcalc.InterfaceAddress{Interface: interfaceName(fields[0]), Address: prefix})
+			}
+			continue
+		}
+
+		if (fields[0] != "inet" && fields[0] != "inet6") || len(fields) < 2 {
+			continue
+		}
+		addr := fields[1]
+		if len(fields) > 3 && fields[2] == "peer" {
+			// Point-to-point links take the prefix length from the peer:
+			// "inet 10.0.0.1 peer 10.0.0.2/32".
+			if _, bits, ok := strings.Cut(fields[3], "/"); ok {
+				addr += "/" + bits
+			}
+		}
+		prefix, err := subnetcalc.ParsePrefixOrAddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %s", addr, err)
+		}
+		addrs = append(addrs, subnetcalc.InterfaceAddress{Interface: iface, Address: prefix})
+	}
+	return addrs, nil
+}
+
+// subnetColumns formats the tab-separated network, broadcast, mask and total
+// columns for prefix. IPv6 prefixes have no broadcast address or mask, so
+// those columns show "-" and the prefix length.
+func subnetColumns(prefix netip.Prefix) string {
+	result, err := subnetcalc.NewCalculator(subnetcalc.WithIPv6Policy(subnetcalc.IPv6Allow)).Calculate(prefix)
+	if err != nil {
+		return fmt.Sprintf("%s\t-\t-\t-", prefix.Masked().Addr())
+	}
+	if result.IPv6 != nil {
+		return fmt.Sprintf("%s\t-\t/%d\t%s", result.NetworkAddress, result.Prefix.Bits(), result.TotalIPs)
+	}
+	return fmt.Sprintf("%s\t%s\t%s\t%s", result.NetworkAddress, result.BroadcastAddress, result.SubnetMask, result.TotalIPs)
+}
+
+// interfaceName strips the trailing colon and the "@peer" suffix of veth and
+// VLAN interfaces.
+func interfaceName(s string) string {
+	s = strings.TrimSuffix(s, ":")
+	if i := strings.IndexByte(s, '@'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+func startsWithSpace(line string) bool {
+	return line != "" && unicode.IsSpace(rune(line[0]))
+}
+
+func init() {
+	rootCmd.AddCommand(fromIPRouteCmd, fromIPAddrCmd)
+}
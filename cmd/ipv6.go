@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// writeIPv6Result writes the notations and usable range of an IPv6 result
+// to w.
+func writeIPv6Result(w io.Writer, result subnetcalc.Result) {
+	info := result.IPv6
+	fmt.Fprintf(w, "Address:            %s\n", info.Compressed)
+	if info.Zone != "" {
+		fmt.Fprintf(w, "Zone:               %s\n", info.Zone)
+	}
+	fmt.Fprintf(w, "Expanded:           %s\n", info.Expanded)
+	fmt.Fprintf(w, "Reverse:            %s\n", info.Reverse)
+	fmt.Fprintf(w, "Network:            %s\n", result.Prefix)
+	fmt.Fprintf(w, "Prefix (binary):    %s\n", info.Binary)
+	fmt.Fprintf(w, "Usable Range:       %s - %s\n", result.FirstUsable, result.LastUsable)
+	fmt.Fprintf(w, "Total IPs:          %s\n", result.TotalIPs)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var k8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Kubernetes network planning",
+}
+
+var k8sPlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Compute node, pod and service capacity of a cluster",
+	Long: `Compute node, pod and service capacity of a cluster.
+
+The node count is limited both by the node subnet and by how many per-node
+ranges of --node-cidr-mask-size fit in the pod CIDR. Overlapping ranges make
+the command exit non-zero.`,
+	Example: `snc k8s plan --node-cidr 10.0.0.0/22 --pod-cidr 10.244.0.0/16 --service-cidr 10.96.0.0/12`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		var networks subnetcalc.KubernetesNetworks
+		var err error
+		for _, f := range []struct {
+			flag   string
+			prefix *netip.Prefix
+		}{
+			{"node-cidr", &networks.NodeCIDR},
+			{"pod-cidr", &networks.PodCIDR},
+			{"service-cidr", &networks.ServiceCIDR},
+		} {
+			value, err := cmd.Flags().GetString(f.flag)
+			if err != nil {
+				return err
+			}
+			if *f.prefix, err = netip.ParsePrefix(value); err != nil {
+				return fmt.Errorf("invalid --%s: %s", f.flag, err)
+			}
+		}
+		if networks.MaxPodsPerNode, err = cmd.Flags().GetUint64("max-pods"); err != nil {
+			return err
+		}
+		if networks.NodeCIDRMaskSize, err = cmd.Flags().GetInt("node-cidr-mask-size"); err != nil {
+			return err
+		}
+
+		plan, err := subnetcalc.PlanKubernetes(networks)
+		if err != nil {
+			return fmt.Errorf("error planning cluster: %s", err)
+		}
+
+		fmt.Printf("Nodes (node CIDR):  %d\n", plan.NodesByNodeCIDR)
+		fmt.Printf("Nodes (pod CIDR):   %d\n", plan.NodesByPodCIDR)
+		fmt.Printf("Max Nodes:          %d\n", plan.MaxNodes)
+		fmt.Printf("Pods per Node:      %d\n", plan.PodsPerNode)
+		fmt.Printf("Max Pods:           %d\n", plan.MaxPods)
+		fmt.Printf("Max Services:       %d\n", plan.MaxServices)
+		for _, warning := range plan.Warnings {
+			warnf(os.Stderr, "%s", warning)
+		}
+		for _, overlap := range plan.Overlaps {
+			fmt.Printf("overlap: %s\n", overlap)
+		}
+
+		if len(plan.Overlaps) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d overlapping %s", len(plan.Overlaps), plural(len(plan.Overlaps), "range", "ranges"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	k8sPlanCmd.Flags().String("node-cidr", "", "subnet the nodes are addressed from")
+	k8sPlanCmd.Flags().String("pod-cidr", "", "cluster CIDR split into per-node pod ranges")
+	k8sPlanCmd.Flags().String("service-cidr", "", "service cluster IP range")
+	k8sPlanCmd.Flags().Uint64("max-pods", 110, "maximum pods per node")
+	k8sPlanCmd.Flags().Int("node-cidr-mask-size", 24, "prefix length of each node's pod range")
+	for _, flag := range []string{"node-cidr", "pod-cidr", "service-cidr"} {
+		cobra.CheckErr(k8sPlanCmd.MarkFlagRequired(flag))
+	}
+	k8sCmd.AddCommand(k8sPlanCmd)
+	rootCmd.AddCommand(k8sCmd)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// fileLintFinding is a lint finding with the file it was found in and, with
+// --annotate, the annotation of the prefix on its line.
+type fileLintFinding struct {
+	File                   string `json:"file" yaml:"file"`
+	subnetcalc.LintFinding `yaml:",inline"`
+	Annotation             *subnetcalc.Annotation `json:"annotation,omitempty" yaml:"annotation,omitempty"`
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <file>...",
+	Short: "Check prefix list files for mistakes",
+	Long: `Check prefix list files for mistakes.
+
+Errors are reported for unparseable lines, prefixes with host bits set (such
+as 10.0.0.5/24 where 10.0.0.0/24 was probably meant) and duplicates. Warnings
+are reported for overlapping prefixes, IPv6 prefixes not written in canonical
+RFC 5952 form and, with --public, special-purpose ranges such as RFC 1918 or
+documentation prefixes.
+
+With --annotate, findings on a valid prefix also show what offline MaxMind DB
+or RIR delegated-stats files say about it.
+
+Each finding is printed as "file:line: severity: rule: message", or as a list
+with -o json or -o yaml. The command exits non-zero when any finding is at
+least as severe as --fail-on, so it can run as a pre-commit hook.`,
+	Example: `snc lint prefixes.txt
+snc lint --public --fail-on warning -o json allowlist.txt
+snc lint --public --annotate delegated-ripencc-latest allowlist.txt`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		var opts subnetcalc.LintOptions
+		var err error
+		if opts.Public, err = flags.GetBool("public"); err != nil {
+			return err
+		}
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		failOn, err := flags.GetString("fail-on")
+		if err != nil {
+			return err
+		}
+		if failOn != string(subnetcalc.SeverityError) && failOn != string(subnetcalc.SeverityWarning) {
+			return fmt.Errorf("unknown severity %q", failOn)
+		}
+
+		annotators, err := loadAnnotator(cmd)
+		if err != nil {
+			return err
+		}
+		defer annotators.Close()
+
+		findings := []fileLintFinding{}
+		for _, path := range args {
+			data, err := readInput(path)
+			if err != nil {
+				return err
+			}
+			fileFindings, err := subnetcalc.LintPrefixList(bytes.NewReader(data), opts)
+			if err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+			annotations, err := annotateLines(annotators, data)
+			if err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+			for _, finding := range fileFindings {
+				findings = append(findings, fileLintFinding{File: path, LintFinding: finding, Annotation: annotations[finding.Line]})
+			}
+		}
+
+		if output != subnetcalc.OutputText {
+			if err := subnetcalc.Encode(os.Stdout, findings, output); err != nil {
+				return err
+			}
+		} else {
+			for _, f := range findings {
+				fmt.Printf("%s:%d: %s: %s: %s", f.File, f.Line, f.Severity, f.Rule, f.Message)
+				if f.Annotation != nil {
+					fmt.Printf(" (%s)", annotationText(*f.Annotation))
+				}
+				fmt.Println()
+			}
+		}
+
+		failing := 0
+		for _, f := range findings {
+			if f.Severity == subnetcalc.SeverityError || failOn == string(subnetcalc.SeverityWarning) {
+				failing++
+			}
+		}
+		if failing > 0 {
+			return fmt.Errorf("%d %s found", failing, plural(failing, "problem", "problems"))
+		}
+		return nil
+	},
+}
+
+// annotateLines annotates the prefixes of a prefix list by line number.
+func annotateLines(annotators subnetcalc.Annotators, data []byte) (map[int]*subnetcalc.Annotation, error) {
+	if annotators == nil {
+		return nil, nil
+	}
+	lines, err := subnetcalc.ReadPrefixList(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	annotations := make(map[int]*subnetcalc.Annotation)
+	for _, l := range lines {
+		prefix, err := subnetcalc.ParsePrefixOrAddr(l.Token)
+		if err != nil {
+			continue
+		}
+		if a, ok := annotators.Annotate(prefix.Masked()); ok {
+			annotations[l.Line] = &a
+		}
+	}
+	return annotations, nil
+}
+
+func init() {
+	lintCmd.Flags().Bool("public", false, "the lists hold public address space; report special-purpose ranges")
+	addOutputFlag(lintCmd)
+	addAnnotateFlag(lintCmd.Flags())
+	lintCmd.Flags().String("fail-on", string(subnetcalc.SeverityError), "lowest severity that makes the command fail [error warning]")
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+// writeMulticastInfo writes the multicast details of a result to w, if any.
+// The mapped MAC address is only set for a single group.
+func writeMulticastInfo(w io.Writer, info *subnetcalc.MulticastInfo) {
+	if info == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "Multicast Scope:    %s\n", info.Scope)
+	if info.SSM {
+		fmt.Fprintf(w, "Source-Specific:    yes\n")
+	}
+	switch {
+	case info.GLOP && info.GLOPAS != 0:
+		fmt.Fprintf(w, "GLOP AS:            %d\n", info.GLOPAS)
+	case info.GLOP:
+		fmt.Fprintf(w, "GLOP:               yes\n")
+	}
+	if info.MAC == nil {
+		return
+	}
+	fmt.Fprintf(w, "Multicast MAC:      %s\n", info.MAC)
+	if len(info.SharedMAC) > 0 {
+		fmt.Fprintln(w, "Groups Sharing MAC:")
+		for _, shared := range info.SharedMAC {
+			fmt.Fprintf(w, "  %s\n", shared)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Work with YAML address plans",
+	Long: `Work with YAML address plans.
+
+A plan nests sites, VPCs and subnets, each with a name, an optional purpose
+and a prefix:
+
+  sites:
+    - name: fra1
+      purpose: Frankfurt data center
+      prefix: 10.0.0.0/12
+      vpcs:
+        - name: prod
+          prefix: 10.0.0.0/16
+          subnets:
+            - name: web
+              purpose: public web tier
+              prefix: 10.0.1.0/24`,
+}
+
+var planValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check an address plan for mistakes",
+	Long: `Check an address plan for mistakes.
+
+Every child must lie inside its parent, siblings must not overlap and prefixes
+must not have host bits set. The utilization of every site and VPC is printed
+first; any problems found make the command exit non-zero. The plan is read
+from stdin when no file is given.`,
+	Example: `snc plan validate plan.yaml`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plan, err := readPlan(inputPath(args))
+		if err != nil {
+			return err
+		}
+
+		report := subnetcalc.ValidatePlan(plan)
+		for _, u := range report.Utilization {
+			fmt.Printf("%s%-*s %-18s %5.1f%%\n", strings.Repeat("  ", u.Depth), 24-2*u.Depth, u.Path, formatPrefix(u.Prefix), u.Utilization*100)
+		}
+		for _, f := range report.Findings {
+			fmt.Printf("%s: %s\n", f.Path, f.Problem)
+		}
+		if len(report.Findings) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d %s found", len(report.Findings), plural(len(report.Findings), "problem", "problems"))
+		}
+		return nil
+	},
+}
+
+func readPlan(path string) (subnetcalc.Plan, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return subnetcalc.Plan{}, err
+	}
+	return subnetcalc.ParsePlan(data)
+}
+
+func init() {
+	planCmd.AddCommand(planValidateCmd)
+	rootCmd.AddCommand(planCmd)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var rdnsCmd = &cobra.Command{
+	Use:   "rdns <cidr>",
+	Short: "Show the reverse DNS zones for a prefix",
+	Long: `Show the in-addr.arpa or ip6.arpa zones for a prefix.
+
+IPv4 prefixes longer than /24 use RFC 2317 classless delegation; the CNAME
+records the parent zone has to publish are listed as well. With --zonefile a
+BIND-format skeleton with PTR records is printed instead.
+
+The hostname template understands {ip}, {a}, {b}, {c}, {d} and {n}.`,
+	Example: `# zones for a /20
+snc rdns 10.20.16.0/20
+
+# zone file for a classless /27
+snc rdns 172.16.38.64/27 --zonefile --template 'host{n}.example.net.'`,
+	ValidArgsFunction: completePrefix,
+	Args:              cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+
+		info, err := subnetcalc.ReverseDNS(prefix)
+		if err != nil {
+			return fmt.Errorf("error calculating reverse zones: %s", err)
+		}
+
+		zonefile, err := cmd.Flags().GetBool("zonefile")
+		if err != nil {
+			return err
+		}
+		if zonefile {
+			return writeZoneFiles(cmd, os.Stdout, prefix, info)
+		}
+
+		fmt.Println("Zones:")
+		for _, zone := range info.Zones {
+			fmt.Printf("  %-40s %s\n", zone.Name, zone.Prefix)
+		}
+		if info.Classless != nil {
+			ns, err := cmd.Flags().GetString("ns")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nRFC 2317 records for %s:\n", info.Classless.ParentZone)
+			writeDelegation(os.Stdout, info.Classless, ns)
+		}
+		return nil
+	},
+}
+
+// writeZoneFiles writes the reverse zone file skeletons of prefix to w,
+// followed by the records to add to the parent zone for a classless
+// delegation.
+func writeZoneFiles(cmd *cobra.Command, w io.Writer, prefix netip.Prefix, info subnetcalc.ReverseDNSInfo) error {
+	var opts subnetcalc.ZoneFileOptions
+	var err error
+	if opts.HostTemplate, err = cmd.Flags().GetString("template"); err != nil {
+		return err
+	}
+	if opts.NameServer, err = cmd.Flags().GetString("ns"); err != nil {
+		return err
+	}
+	if opts.Contact, err = cmd.Flags().GetString("contact"); err != nil {
+		return err
+	}
+	if opts.TTL, err = cmd.Flags().GetUint32("ttl"); err != nil {
+		return err
+	}
+
+	files, err := subnetcalc.ReverseZoneFiles(prefix, opts)
+	if err != nil {
+		return fmt.Errorf("error building zone file: %s", err)
+	}
+	for i, file := range files {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprint(w, file)
+	}
+	if info.Classless != nil {
+		fmt.Fprintf(w, "\n; add to the parent zone %s\n", info.Classless.ParentZone)
+		writeDelegation(w, info.Classless, opts.NameServer)
+	}
+	return nil
+}
+
+// writeDelegation writes the NS and CNAME records of an RFC 2317 classless
+// delegation to w.
+func writeDelegation(w io.Writer, delegation *subnetcalc.ClasslessDelegation, nameServer string) {
+	fmt.Fprintf(w, "%s\tIN\tNS\t%s\n", delegation.Zone, nameServer)
+	for _, rr := range delegation.Records {
+		fmt.Fprintf(w, "%s\tIN\t%s\t%s\n", rr.Name, rr.Type, rr.Value)
+	}
+}
+
+func init() {
+	rdnsCmd.Flags().Bool("zonefile", false, "print a BIND zone file skeleton")
+	rdnsCmd.Flags().String("template", "host-{ip}.example.com.", "hostname template for PTR records")
+	rdnsCmd.Flags().String("ns", "ns1.example.com.", "name server for the SOA and NS records")
+	rdnsCmd.Flags().String("contact", "hostmaster.example.com.", "SOA contact mailbox")
+	rdnsCmd.Flags().Uint32("ttl", 3600, "default TTL of the zone")
+	rootCmd.AddCommand(rdnsCmd)
+}
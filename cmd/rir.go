@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var rirCmd = &cobra.Command{
+	Use:   "rir",
+	Short: "Work with RIR delegated-stats files",
+}
+
+var rirParseCmd = &cobra.Command{
+	Use:   "parse [file]",
+	Short: "Convert RIR delegated-stats records to CIDRs",
+	Long: `Convert RIR delegated-stats records to CIDRs.
+
+IPv4 records of delegated-stats files give a start address and an address
+count that is often not a power of two, so one record may become several
+CIDRs. Each CIDR is printed with its country, registry and status.
+
+--country and --status keep only matching records, --aggregate merges the
+remaining space into the fewest CIDRs, and --summary prints one line per
+country or registry instead. The file is read from stdin when none is given.`,
+	Example: `# all CIDRs delegated to the Netherlands, merged
+snc rir parse --country NL --aggregate delegated-ripencc-latest
+
+# address space per country
+snc rir parse --summary country delegated-ripencc-latest`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		countries, err := flags.GetStringSlice("country")
+		if err != nil {
+			return err
+		}
+		statuses, err := flags.GetStringSlice("status")
+		if err != nil {
+			return err
+		}
+		aggregate, err := flags.GetBool("aggregate")
+		if err != nil {
+			return err
+		}
+		summary, err := flags.GetString("summary")
+		if err != nil {
+			return err
+		}
+
+		f, err := openInput(inputPath(args))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		records, err := subnetcalc.ParseDelegatedStats(f)
+		if err != nil {
+			return err
+		}
+
+		var selected []subnetcalc.DelegatedRecord
+		for _, r := range records {
+			if (len(countries) == 0 || slices.Contains(countries, r.Country)) &&
+				(len(statuses) == 0 || slices.Contains(statuses, r.Status)) {
+				selected = append(selected, r)
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		switch {
+		case summary != "":
+			summaries, err := subnetcalc.SummarizeDelegated(selected, subnetcalc.DelegatedGrouping(summary))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\tRECORDS\tIPV4 ADDRESSES\tIPV6 PREFIXES\tCIDRS\n", strings.ToUpper(summary))
+			for _, s := range summaries {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", s.Key, s.Records, s.IPv4Addresses, s.IPv6Prefixes, len(s.Prefixes))
+			}
+		case aggregate:
+			var prefixes []netip.Prefix
+			for _, r := range selected {
+				prefixes = append(prefixes, r.Prefixes...)
+			}
+			for _, p := range subnetcalc.NewPrefixSet(prefixes...).Prefixes() {
+				fmt.Fprintln(w, p)
+			}
+		default:
+			for _, r := range selected {
+				for _, p := range r.Prefixes {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p, r.Country, r.Registry, r.Status)
+				}
+			}
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rirParseCmd.Flags().StringSlice("country", nil, "only include records of these country codes")
+	rirParseCmd.Flags().StringSlice("status", nil, "only include records with these statuses (allocated, assigned, available, reserved)")
+	rirParseCmd.Flags().Bool("aggregate", false, "merge the selected space into the fewest CIDRs")
+	rirParseCmd.Flags().String("summary", "", fmt.Sprintf("print one line per group %v", subnetcalc.DelegatedGroupings))
+	rirParseCmd.MarkFlagsMutuallyExclusive("aggregate", "summary")
+	rirCmd.AddCommand(rirParseCmd)
+	rootCmd.AddCommand(rirCmd)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/netip"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/suraiborys/subnetcalc/app/subnetcalc"
@@ -12,32 +16,142 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "snc <cidr>",
 	Short: "Calculate subnet information from CIDR notation",
-	Long:  "Calculate subnet information from CIDR notation.",
+	Long: `Calculate subnet information from CIDR notation.
+
+A prefix with host bits set, such as 172.16.38.94/27, is normalized to its
+network (172.16.38.64/27) with a warning; --strict rejects it instead.
+
+IPv6 addresses and prefixes, optionally with a zone such as fe80::1%eth0, are
+shown in compressed, expanded, reverse nibble and binary form. Multicast
+inputs also show their scope, SSM or GLOP details and, for a single group, the
+Ethernet MAC address it maps to and the other groups sharing that MAC.
+
+--output json or --output yaml prints the result, including multicast details
+and annotations, as a document with snake_case keys for scripts.
+
+Defaults for --output, --provider, --strict and --color can be set in
+~/.config/snc/config.yaml or with SNC_* environment variables; see
+"snc config". A configured provider only applies to the IPv4 prefixes it
+allows, while --provider rejects the others.`,
 	Example: `# calculate subnet information for 192.168.1.0/24
-snc 192.168.1.0/24`,
-	Version: "0.1.0",
-	Args:    cobra.ExactArgs(1),
-	RunE: func(_ *cobra.Command, args []string) error {
-		prefix, err := netip.ParsePrefix(args[0])
+snc 192.168.1.0/24
+
+# IPv6 notations of a zone-scoped address
+snc fe80::1%eth0/64
+
+# usable addresses of an AWS subnet
+snc --provider aws 10.0.1.0/24
+
+# machine-readable result
+snc -o json 10.0.1.0/24
+
+# origin AS and country from offline GeoLite2 and RIR files
+snc --annotate GeoLite2-ASN.mmdb --annotate delegated-ripencc-latest 193.0.0.0/21`,
+	Version:           "0.1.0",
+	ValidArgsFunction: completeZonedPrefix,
+	Args:              cobra.ExactArgs(1),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applySettings(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		providerName, err := cmd.Flags().GetString("provider")
+		if err != nil {
+			return err
+		}
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+		output, err := outputFormat(cmd)
 		if err != nil {
-			return fmt.Errorf("invalid prefix: %s", err)
+			return err
+		}
 
+		annotators, err := loadAnnotator(cmd)
+		if err != nil {
+			return err
 		}
+		defer annotators.Close()
 
-		result, err := subnetcalc.CalcSubnetInfo(prefix)
+		prefix, zone, err := parseRootInput(args[0])
 		if err != nil {
-			return fmt.Errorf("error calculating subnet info: %s", err)
+			return err
+		}
+		opts := []subnetcalc.Option{subnetcalc.WithStrict(strict), subnetcalc.WithIPv6Policy(subnetcalc.IPv6Allow)}
+		if providerName != "" {
+			provider, err := subnetcalc.ParseProvider(providerName)
+			if err != nil {
+				return err
+			}
+			// A provider from the config file or environment is a default
+			// for the prefixes it fits; only --provider insists on it.
+			switch {
+			case !cmd.Flags().Changed("provider"):
+				if provider.Allows(prefix) {
+					opts = append(opts, subnetcalc.WithProvider(provider))
+				}
+			case prefix.Addr().Is6():
+				cmd.SilenceUsage = true
+				return errors.New("--provider only applies to IPv4 prefixes")
+			default:
+				opts = append(opts, subnetcalc.WithProvider(provider))
+			}
+		}
+		if annotators != nil {
+			opts = append(opts, subnetcalc.WithAnnotator(annotators))
 		}
 
-		fmt.Printf("Network Address:    %s\n", result.NetworkAddress)
-		fmt.Printf("Broadcast Address:  %s\n", result.BroadcastIP)
-		fmt.Printf("Subnet Mask:        %s\n", result.SubnetMask)
-		fmt.Printf("Total IPs:          %d\n", result.TotalIP)
+		result, err := subnetcalc.NewCalculator(opts...).Calculate(prefix)
+		var hostBits *subnetcalc.HostBitsError
+		if errors.As(err, &hostBits) {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if err != nil {
+			// Prefixes the provider does not allow are not usage errors.
+			cmd.SilenceUsage = providerName != ""
+			return fmt.Errorf("error calculating subnet info: %s", err)
+		}
+		if result.Normalized() {
+			warnf(os.Stderr, "%s normalized to %s", result.Input, result.Prefix)
+		}
+		if result.IPv6 != nil {
+			result.IPv6.Zone = zone
+		}
+		if output != subnetcalc.OutputText {
+			return subnetcalc.Encode(os.Stdout, result, output)
+		}
 
+		if result.IPv6 != nil {
+			writeIPv6Result(os.Stdout, result)
+		} else {
+			writeIPv4Result(os.Stdout, result)
+		}
+		if annotators != nil {
+			writeAnnotation(os.Stdout, result.Annotation)
+		}
+		writeMulticastInfo(os.Stdout, result.Multicast)
 		return nil
 	},
 }
 
+// writeIPv4Result writes an IPv4 result to w and, when a provider is
+// selected, its usable count and reserved addresses.
+func writeIPv4Result(w io.Writer, result subnetcalc.Result) {
+	fmt.Fprintf(w, "Network Address:    %s\n", result.NetworkAddress)
+	fmt.Fprintf(w, "Broadcast Address:  %s\n", result.BroadcastAddress)
+	fmt.Fprintf(w, "Subnet Mask:        %s\n", result.SubnetMask)
+	fmt.Fprintf(w, "Total IPs:          %s\n", result.TotalIPs)
+
+	if len(result.Reserved) > 0 {
+		fmt.Fprintf(w, "Usable IPs:         %s\n", result.UsableIPs)
+		fmt.Fprintln(w, "Reserved:")
+		for _, r := range result.Reserved {
+			fmt.Fprintf(w, "  %-17s %s\n", r.Address, r.Reason)
+		}
+	}
+}
+
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
@@ -48,5 +162,50 @@ func Execute() {
 func Root() *cobra.Command { return rootCmd }
 
 func init() {
-	// Flags and configuration can go here
+	rootCmd.PersistentFlags().String("color", "auto", fmt.Sprintf("colorize output %v", colorModes))
+	addAnnotateFlag(rootCmd.Flags())
+	addOutputFlag(rootCmd)
+	rootCmd.Flags().Bool("strict", false, "reject prefixes with host bits set instead of normalizing them")
+	rootCmd.Flags().String("provider", "", fmt.Sprintf("cloud provider whose reserved addresses apply %v", subnetcalc.Providers))
+
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("color", cobra.FixedCompletions(colorModes, cobra.ShellCompDirectiveNoFileComp)))
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("provider", cobra.FixedCompletions(formatNames(subnetcalc.Providers), cobra.ShellCompDirectiveNoFileComp)))
+}
+
+// addOutputFlag adds the -o/--output flag shared by the commands that can
+// print their results as JSON or YAML.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", string(subnetcalc.OutputText), fmt.Sprintf("output format %v", subnetcalc.OutputFormats))
+	cobra.CheckErr(cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(formatNames(subnetcalc.OutputFormats), cobra.ShellCompDirectiveNoFileComp)))
+}
+
+// outputFormat returns the validated --output flag of cmd.
+func outputFormat(cmd *cobra.Command) (subnetcalc.OutputFormat, error) {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return "", err
+	}
+	format := subnetcalc.OutputFormat(output)
+	if !slices.Contains(subnetcalc.OutputFormats, format) {
+		return "", fmt.Errorf("unknown output format %q", output)
+	}
+	return format, nil
+}
+
+// parseRootInput parses the argument of the root command. A bare address is
+// taken as a host prefix. IPv6 input may carry a zone, which is returned
+// separately since prefixes cannot hold one.
+func parseRootInput(s string) (netip.Prefix, string, error) {
+	if !strings.Contains(s, ":") {
+		prefix, err := subnetcalc.ParsePrefixOrAddr(s)
+		if err != nil {
+			return netip.Prefix{}, "", fmt.Errorf("invalid prefix: %s", err)
+		}
+		return prefix, "", nil
+	}
+	info, err := subnetcalc.ParseIPv6(s)
+	if err != nil {
+		return netip.Prefix{}, "", fmt.Errorf("invalid IPv6 address: %s", err)
+	}
+	return netip.PrefixFrom(info.Address, info.Prefix.Bits()), info.Zone, nil
 }
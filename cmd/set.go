@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set operations on prefix list files",
+	Long: `Set operations on prefix list files.
+
+Each file holds one prefix per line; blank lines and '#' comments are ignored
+and "-" reads from stdin. Results are printed as the minimal list of CIDRs.`,
+	Example: `# addresses allowed in prod but not in staging
+snc set subtract prod.txt staging.txt`,
+}
+
+var setUnionCmd = &cobra.Command{
+	Use:   "union <file> <file>...",
+	Short: "Print the prefixes covered by any of the files",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sets, err := readPrefixSets(args)
+		if err != nil {
+			return err
+		}
+		result := sets[0]
+		for _, s := range sets[1:] {
+			result = result.Union(s)
+		}
+		return writePrefixSet(cmd, os.Stdout, result)
+	},
+}
+
+var setIntersectCmd = &cobra.Command{
+	Use:   "intersect <file> <file>...",
+	Short: "Print the prefixes covered by every file",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sets, err := readPrefixSets(args)
+		if err != nil {
+			return err
+		}
+		result := sets[0]
+		for _, s := range sets[1:] {
+			result = result.Intersect(s)
+		}
+		return writePrefixSet(cmd, os.Stdout, result)
+	},
+}
+
+var setSubtractCmd = &cobra.Command{
+	Use:   "subtract <file> <file>...",
+	Short: "Print the prefixes of the first file not covered by the others",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sets, err := readPrefixSets(args)
+		if err != nil {
+			return err
+		}
+		result := sets[0]
+		for _, s := range sets[1:] {
+			result = result.Subtract(s)
+		}
+		return writePrefixSet(cmd, os.Stdout, result)
+	},
+}
+
+var setComplementCmd = &cobra.Command{
+	Use:   "complement <file>",
+	Short: "Print the prefixes not covered by the file",
+	Long: `Print the prefixes not covered by the file.
+
+Without --within the complement is taken over the whole address space of each
+address family that appears in the file.`,
+	Example: `# free space left in 10.0.0.0/8
+snc set complement --within 10.0.0.0/8 allocated.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefixes, err := readPrefixFile(args[0])
+		if err != nil {
+			return err
+		}
+		within, err := cmd.Flags().GetStringSlice("within")
+		if err != nil {
+			return err
+		}
+
+		var universe []netip.Prefix
+		for _, w := range within {
+			prefix, err := netip.ParsePrefix(w)
+			if err != nil {
+				return fmt.Errorf("invalid prefix: %s", err)
+			}
+			universe = append(universe, prefix)
+		}
+		if len(universe) == 0 {
+			universe = familyUniverse(prefixes)
+		}
+
+		return writePrefixSet(cmd, os.Stdout, subnetcalc.NewPrefixSet(universe...).Subtract(subnetcalc.NewPrefixSet(prefixes...)))
+	},
+}
+
+var setContainsCmd = &cobra.Command{
+	Use:   "contains <file> <ip|cidr>...",
+	Short: "Check whether addresses or prefixes are covered by the file",
+	Long: `Check whether addresses or prefixes are covered by the file.
+
+The command exits non-zero if any argument is not fully covered.`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefixes, err := readPrefixFile(args[0])
+		if err != nil {
+			return err
+		}
+		set := subnetcalc.NewPrefixSet(prefixes...)
+		annotators, err := loadAnnotator(cmd)
+		if err != nil {
+			return err
+		}
+		defer annotators.Close()
+
+		missing := 0
+		for _, arg := range args[1:] {
+			prefix, err := subnetcalc.ParsePrefixOrAddr(arg)
+			if err != nil {
+				return fmt.Errorf("invalid prefix: %s", err)
+			}
+			if set.ContainsPrefix(prefix) {
+				fmt.Printf("%s: yes%s\n", arg, annotationSuffix(annotators, prefix))
+			} else {
+				fmt.Printf("%s: no%s\n", arg, annotationSuffix(annotators, prefix))
+				missing++
+			}
+		}
+		if missing > 0 {
+			return fmt.Errorf("%d of %d not contained", missing, len(args)-1)
+		}
+		return nil
+	},
+}
+
+func readPrefixSets(paths []string) ([]subnetcalc.PrefixSet, error) {
+	sets := make([]subnetcalc.PrefixSet, 0, len(paths))
+	for _, path := range paths {
+		prefixes, err := readPrefixFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, subnetcalc.NewPrefixSet(prefixes...))
+	}
+	return sets, nil
+}
+
+// familyUniverse returns the default routes of the address families used by prefixes.
+func familyUniverse(prefixes []netip.Prefix) []netip.Prefix {
+	var has4, has6 bool
+	for _, prefix := range prefixes {
+		if prefix.Addr().Is4() {
+			has4 = true
+		} else {
+			has6 = true
+		}
+	}
+	var universe []netip.Prefix
+	if has4 {
+		universe = append(universe, netip.MustParsePrefix("0.0.0.0/0"))
+	}
+	if has6 {
+		universe = append(universe, netip.MustParsePrefix("::/0"))
+	}
+	return universe
+}
+
+// writePrefixSet writes the prefixes of set to w, one per line, annotated
+// when --annotate is given.
+func writePrefixSet(cmd *cobra.Command, w io.Writer, set subnetcalc.PrefixSet) error {
+	annotators, err := loadAnnotator(cmd)
+	if err != nil {
+		return err
+	}
+	defer annotators.Close()
+	for _, prefix := range set.Prefixes() {
+		fmt.Fprintf(w, "%s%s\n", prefix, annotationSuffix(annotators, prefix))
+	}
+	return nil
+}
+
+func init() {
+	addAnnotateFlag(setCmd.PersistentFlags())
+	setComplementCmd.Flags().StringSlice("within", nil, "complement within these prefixes instead of the whole address space")
+	setCmd.AddCommand(setUnionCmd, setIntersectCmd, setSubtractCmd, setComplementCmd, setContainsCmd)
+	rootCmd.AddCommand(setCmd)
+}
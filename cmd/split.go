@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <cidr>",
+	Short: "Split a prefix into equal subnets",
+	Long: `Split a prefix into equal subnets.
+
+With --to, list the subnets of that length and how many fit. Only the first
+--limit subnets are printed, since an IPv6 prefix can hold billions.
+
+Without --to, an IPv6 prefix is summarized by nibble boundary down to /64,
+showing how many subnets of each length fit. Splits that are not on a nibble
+boundary print a warning because reverse DNS can only be delegated per
+nibble.`,
+	Example: `# how many /52s, /56s, /60s and /64s fit in a /48
+snc split 2001:db8:abcd::/48
+
+# list the /56 site allocations of a /48
+snc split --to 56 2001:db8:abcd::/48`,
+	ValidArgsFunction: completePrefix,
+	Args:              cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+		to, err := cmd.Flags().GetInt("to")
+		if err != nil {
+			return err
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return err
+		}
+
+		if to == 0 {
+			if !prefix.Addr().Is6() {
+				return errors.New("--to is required for IPv4 prefixes")
+			}
+			splits, err := subnetcalc.NibbleSplits(prefix)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+			for _, s := range splits {
+				fmt.Fprintf(w, "/%d\t%s\t\n", s.Bits, s.Count)
+			}
+			return w.Flush()
+		}
+
+		split, err := subnetcalc.SplitPrefix(prefix, to, limit)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s holds %s /%d subnets\n", split.Prefix, split.Count, split.Bits)
+		annotators, err := loadAnnotator(cmd)
+		if err != nil {
+			return err
+		}
+		defer annotators.Close()
+		for _, s := range split.Subnets {
+			fmt.Printf("%s%s\n", s, annotationSuffix(annotators, s))
+		}
+		if shown := big.NewInt(int64(len(split.Subnets))); shown.Cmp(split.Count) < 0 {
+			fmt.Printf("... %d of %s shown\n", len(split.Subnets), split.Count)
+		}
+		for _, warning := range split.Warnings {
+			warnf(os.Stderr, "%s", warning)
+		}
+		return nil
+	},
+}
+
+func init() {
+	splitCmd.Flags().Int("to", 0, "prefix length of the subnets")
+	splitCmd.Flags().Int("limit", 256, "maximum number of subnets to list")
+	addAnnotateFlag(splitCmd.Flags())
+	rootCmd.AddCommand(splitCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var tfCmd = &cobra.Command{
+	Use:   "tf",
+	Short: "Terraform-compatible CIDR functions",
+	Long: `Terraform-compatible CIDR functions.
+
+The subcommands behave exactly like the Terraform functions of the same name,
+so plans can be checked offline. Negative numbers have to follow "--" so they
+are not mistaken for flags.`,
+}
+
+var tfCIDRSubnetCmd = &cobra.Command{
+	Use:               "cidrsubnet <prefix> <newbits> <netnum>",
+	Short:             "Calculate a subnet address within a prefix",
+	Example:           `snc tf cidrsubnet 10.0.0.0/16 8 2`,
+	ValidArgsFunction: completePrefix,
+	Args:              cobra.ExactArgs(3),
+	RunE: func(_ *cobra.Command, args []string) error {
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+		newbits, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid newbits: %s", err)
+		}
+		netnum, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid netnum: %s", err)
+		}
+
+		subnet, err := subnetcalc.CIDRSubnet(prefix, newbits, netnum)
+		if err != nil {
+			return err
+		}
+		fmt.Println(subnet)
+		return nil
+	},
+}
+
+var tfCIDRSubnetsCmd = &cobra.Command{
+	Use:               "cidrsubnets <prefix> <newbits>...",
+	Short:             "Calculate a sequence of consecutive subnets within a prefix",
+	Example:           `snc tf cidrsubnets 10.1.0.0/16 4 4 8 4`,
+	ValidArgsFunction: completePrefix,
+	Args:              cobra.MinimumNArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+		newbits := make([]int, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			bits, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid newbits: %s", err)
+			}
+			newbits = append(newbits, bits)
+		}
+
+		subnets, err := subnetcalc.CIDRSubnets(prefix, newbits...)
+		if err != nil {
+			return err
+		}
+		for _, subnet := range subnets {
+			fmt.Println(subnet)
+		}
+		return nil
+	},
+}
+
+var tfCIDRHostCmd = &cobra.Command{
+	Use:   "cidrhost <prefix> <hostnum>",
+	Short: "Calculate a full host IP address within a prefix",
+	Example: `snc tf cidrhost 10.12.112.0/20 268
+
+# last address of the prefix
+snc tf cidrhost -- 10.12.112.0/20 -1`,
+	ValidArgsFunction: completePrefix,
+	Args:              cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+		hostnum, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid hostnum: %s", err)
+		}
+
+		host, err := subnetcalc.CIDRHost(prefix, hostnum)
+		if err != nil {
+			return err
+		}
+		fmt.Println(host)
+		return nil
+	},
+}
+
+var tfCIDRNetmaskCmd = &cobra.Command{
+	Use:               "cidrnetmask <prefix>",
+	Short:             "Convert an IPv4 prefix into a subnet mask",
+	Example:           `snc tf cidrnetmask 172.16.0.0/12`,
+	ValidArgsFunction: completePrefix,
+	Args:              cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		prefix, err := netip.ParsePrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid prefix: %s", err)
+		}
+
+		mask, err := subnetcalc.CIDRNetmask(prefix)
+		if err != nil {
+			return err
+		}
+		fmt.Println(mask)
+		return nil
+	},
+}
+
+func init() {
+	tfCmd.AddCommand(tfCIDRSubnetCmd, tfCIDRSubnetsCmd, tfCIDRHostCmd, tfCIDRNetmaskCmd)
+	rootCmd.AddCommand(tfCmd)
+}
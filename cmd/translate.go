@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var translateCmd = &cobra.Command{
+	Use:   "translate <address>",
+	Short: "Translate between IPv4 and IPv4-embedded IPv6 addresses",
+	Long: `Translate between IPv4 and IPv4-embedded IPv6 addresses.
+
+An IPv4 address is shown in IPv4-mapped (::ffff:a.b.c.d), 6to4 (2002::/16)
+and NAT64 form. An IPv6 address is checked against each of these forms and the
+embedded IPv4 address is printed for every form that matches.
+
+NAT64 addresses follow RFC 6052 and use the well-known prefix 64:ff9b::/96
+unless --nat64 names a network-specific prefix of length 32, 40, 48, 56, 64
+or 96.`,
+	Example: `snc translate 192.0.2.33
+snc translate --nat64 2001:db8:122::/48 2001:db8:122:c000:2:2100::`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nat64, err := cmd.Flags().GetString("nat64")
+		if err != nil {
+			return err
+		}
+		nat64Prefix, err := netip.ParsePrefix(nat64)
+		if err != nil {
+			return fmt.Errorf("invalid --nat64: %s", err)
+		}
+		addr, err := netip.ParseAddr(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid address: %s", err)
+		}
+
+		if addr.Is4() {
+			mapped, err := subnetcalc.MapIPv4(addr)
+			if err != nil {
+				return err
+			}
+			sixToFour, err := subnetcalc.SixToFourPrefix(addr)
+			if err != nil {
+				return err
+			}
+			embedded, err := subnetcalc.EmbedIPv4(nat64Prefix, addr)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("IPv4-Mapped:        %s\n", mapped)
+			fmt.Printf("6to4 Prefix:        %s\n", sixToFour)
+			fmt.Printf("NAT64:              %s\n", embedded)
+			return nil
+		}
+
+		found := false
+		if v4, err := subnetcalc.UnmapIPv4(addr); err == nil {
+			fmt.Printf("IPv4 (mapped):      %s\n", v4)
+			found = true
+		}
+		if v4, err := subnetcalc.SixToFourIPv4(addr); err == nil {
+			fmt.Printf("IPv4 (6to4):        %s\n", v4)
+			found = true
+		}
+		if v4, err := subnetcalc.ExtractIPv4(nat64Prefix, addr); err == nil {
+			fmt.Printf("IPv4 (NAT64):       %s\n", v4)
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("%s does not embed an IPv4 address (NAT64 prefix %s)", addr, nat64Prefix)
+		}
+		return nil
+	},
+}
+
+func init() {
+	translateCmd.Flags().String("nat64", subnetcalc.WellKnownNAT64Prefix.String(), "NAT64 prefix (RFC 6052)")
+	rootCmd.AddCommand(translateCmd)
+}
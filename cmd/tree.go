@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree [file]",
+	Short: "Render prefixes as a nested address map",
+	Long: `Render prefixes as a nested address map.
+
+Each prefix is shown under the smallest prefix that contains it, together with
+the free gaps its parent does not allocate and a bar showing how much of it is
+used. The input is a prefix list with optional labels, or a YAML address plan
+when the file ends in .yaml or .yml. It is read from stdin when no file is
+given.
+
+The html and svg formats produce a standalone page or image of the same tree.
+
+With --annotate, each label is followed by what offline MaxMind DB or RIR
+delegated-stats files say about the prefix.`,
+	Example: `snc tree subnets.txt
+snc tree --format svg plan.yaml > address-map.svg`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		path := inputPath(args)
+		var prefixes []subnetcalc.LabeledPrefix
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml":
+			plan, err := readPlan(path)
+			if err != nil {
+				return err
+			}
+			prefixes = plan.Prefixes()
+		default:
+			if prefixes, err = readPlanFile(path); err != nil {
+				return err
+			}
+		}
+
+		annotators, err := loadAnnotator(cmd)
+		if err != nil {
+			return err
+		}
+		defer annotators.Close()
+		for i, p := range prefixes {
+			if a, ok := annotators.Annotate(p.Prefix); ok {
+				prefixes[i].Label = strings.TrimSpace(p.Label + "  " + annotationText(a))
+			}
+		}
+
+		tree, err := subnetcalc.RenderTree(subnetcalc.BuildTree(prefixes), subnetcalc.TreeFormat(format))
+		if err != nil {
+			return fmt.Errorf("error rendering tree: %s", err)
+		}
+		fmt.Print(tree)
+		return nil
+	},
+}
+
+func init() {
+	treeCmd.Flags().StringP("format", "f", string(subnetcalc.TreeText), fmt.Sprintf("output format %v", subnetcalc.TreeFormats))
+	addAnnotateFlag(treeCmd.Flags())
+	rootCmd.AddCommand(treeCmd)
+}
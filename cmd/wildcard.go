@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+	"github.com/suraiborys/subnetcalc/app/subnetcalc"
+)
+
+var wildcardCmd = &cobra.Command{
+	Use:   "wildcard <address> <wildcard-mask>",
+	Short: "Evaluate a Cisco wildcard mask, including discontiguous ones",
+	Long: `Evaluate a Cisco wildcard mask, including discontiguous ones.
+
+Bits set in the wildcard mask are "don't care" and need not be contiguous, so
+masks such as 0.0.250.0 that no CIDR prefix can express are supported. The
+command prints how many addresses match and how many CIDR prefixes the match
+set decomposes into. Use --list or --cidrs to enumerate them (up to --limit)
+and --test to check a single address, which exits non-zero if it does not
+match.`,
+	Example: `snc wildcard 10.0.5.0 0.0.250.0
+snc wildcard 10.0.5.0 0.0.250.0 --cidrs
+snc wildcard 10.0.5.0 0.0.250.0 --test 10.0.13.0`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, err := netip.ParseAddr(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid address: %s", err)
+		}
+		mask, err := netip.ParseAddr(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid wildcard mask: %s", err)
+		}
+		w, err := subnetcalc.NewWildcard(addr, mask)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		test, err := flags.GetString("test")
+		if err != nil {
+			return err
+		}
+		list, err := flags.GetBool("list")
+		if err != nil {
+			return err
+		}
+		cidrs, err := flags.GetBool("cidrs")
+		if err != nil {
+			return err
+		}
+		limit, err := flags.GetInt("limit")
+		if err != nil {
+			return err
+		}
+
+		if test != "" {
+			testAddr, err := netip.ParseAddr(test)
+			if err != nil {
+				return fmt.Errorf("invalid --test address: %s", err)
+			}
+			if !w.Matches(testAddr) {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%s does not match %s", testAddr, w)
+			}
+			fmt.Printf("%s matches %s\n", testAddr, w)
+			return nil
+		}
+
+		switch {
+		case list:
+			for _, a := range w.Addresses(limit) {
+				fmt.Println(a)
+			}
+		case cidrs:
+			for _, p := range w.Prefixes(limit) {
+				fmt.Println(p)
+			}
+		default:
+			fmt.Printf("Address:            %s\n", w.Address())
+			fmt.Printf("Wildcard Mask:      %s\n", w.Mask())
+			fmt.Printf("Matching IPs:       %d\n", w.Count())
+			fmt.Printf("CIDR Prefixes:      %d\n", w.PrefixCount())
+			if prefix, ok := w.Prefix(); ok {
+				fmt.Printf("Equivalent CIDR:    %s\n", prefix)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	wildcardCmd.Flags().String("test", "", "check whether this address matches")
+	wildcardCmd.Flags().Bool("list", false, "list the matching addresses")
+	wildcardCmd.Flags().Bool("cidrs", false, "list the CIDR prefixes covering exactly the matching addresses")
+	wildcardCmd.Flags().Int("limit", 256, "maximum number of addresses or prefixes to list")
+	wildcardCmd.MarkFlagsMutuallyExclusive("test", "list", "cidrs")
+	rootCmd.AddCommand(wildcardCmd)
+}